@@ -743,6 +743,24 @@ func (h *Hub) internalUserCustom(id int64, o Serializable) error {
 	return nil
 }
 
+// UserEphemeral sends to the connected sessions of the user without
+// keeping the message for sessions that resume, nor giving it a message id.
+// Clients must not count these messages towards the id they resume from.
+// Should only be called from Hub Processing Loop.
+func (h *Hub) UserEphemeral(id int64, o Serializable) error {
+	h.auditUser(id, o)
+	b, err := o.Serialize()
+	if err != nil {
+		return err
+	}
+	for _, wrapper := range h.clients[id] {
+		if wrapper.C != nil {
+			wrapper.C.Msg(b)
+		}
+	}
+	return nil
+}
+
 // func (h *Hub) Custom(o models.Serializable, from Client) bool {
 // 	h.incoming <- &IncomingMessage{
 // 		J:    o,
@@ -1,18 +1,30 @@
 package hubs
 
 import (
+	"context"
 	"fr_book_api/actors"
+	"fr_book_api/models"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 	// -- imports --
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	// -- end --
 )
 
 // BackgroundSetup sets up things for the hub.
 func BackgroundSetup(sugar string, mongoDb *mongo.Database, logger *zap.Logger) error {
 	// -- init --
+	actors.NewHub("background", &BackgroundController{
+		db: mongoDb,
+	}, 100, 10*time.Second, logger).Start()
 	return nil
 	// -- end --
 }
@@ -22,6 +34,19 @@ type BackgroundController struct {
 	h   *actors.Hub
 	log *zap.Logger
 	// -- declarations --
+	db *mongo.Database
+	// tagsRepaired is set once every article's tags have been normalized.
+	tagsRepaired bool
+	// pairsRepaired is set once every friendship and friend request has
+	// its pair stored.
+	pairsRepaired bool
+	// conversationsBuilt is set once every chat message belongs to a
+	// conversation.
+	conversationsBuilt bool
+	conversationId     *models.IDNode
+	// windowRankedAt is when the posts in the ranking window were last
+	// ranked.
+	windowRankedAt time.Time
 	// -- end --
 }
 
@@ -29,6 +54,7 @@ func (bc *BackgroundController) Connect(h *actors.Hub, ct time.Time) {
 	bc.h = h
 	// -- connect --
 	bc.log = h.Log()
+	bc.conversationId, _ = models.NewIDNode(22)
 	// -- end --
 }
 
@@ -72,6 +98,23 @@ func (bc *BackgroundController) ProcessHub(d actors.Serializable, c actors.HubCl
 
 func (bc *BackgroundController) Tick(ct time.Time) {
 	// -- tick --
+	if bc.db == nil {
+		return
+	}
+	bc.closePolls(ct)
+	bc.publishDue("posts", ct, "created_at")
+	bc.publishDue("articles", ct, "published_at")
+	bc.rankPosts(ct)
+	bc.processPdfs()
+	if !bc.tagsRepaired {
+		bc.tagsRepaired = bc.repairTags()
+	}
+	if !bc.pairsRepaired {
+		bc.pairsRepaired = bc.repairPairs("friends") && bc.repairPairs("friend_requests")
+	}
+	if !bc.conversationsBuilt {
+		bc.conversationsBuilt = bc.buildConversations()
+	}
 	// -- end --
 }
 
@@ -92,4 +135,381 @@ func (bc *BackgroundController) Healthz() string {
 }
 
 // -- code --
+
+// closePolls marks every poll whose closing time has passed as closed, so
+// that no further votes are accepted for it.
+func (bc *BackgroundController) closePolls(ct time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := bc.db.Collection("posts").UpdateMany(ctx, bson.M{
+		"poll.closes_at": bson.M{"$lte": ct},
+		"poll.closed":    bson.M{"$ne": true},
+	}, bson.M{"$set": bson.M{"poll.closed": true}})
+	if err != nil {
+		bc.log.Error("Unable to close polls", zap.Error(err))
+		return
+	}
+	if res.ModifiedCount > 0 {
+		bc.log.Info("Closed polls", zap.Int64("count", res.ModifiedCount))
+	}
+}
+
+// publishDue publishes the scheduled items of a collection whose publish
+// time has come, stamping the given fields with the current time. Each
+// item is claimed by a single conditional update on its status, so it is
+// published exactly once even if the hub restarts or ticks overlap.
+func (bc *BackgroundController) publishDue(collection string, ct time.Time, stamps ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set := bson.M{}
+	for _, s := range stamps {
+		set[s] = ct
+	}
+
+	for {
+		var item struct {
+			Id int `bson:"_id"`
+		}
+		err := bc.db.Collection(collection).FindOneAndUpdate(ctx, bson.M{
+			"status":     models.PublishStatusScheduled,
+			"publish_at": bson.M{"$lte": ct},
+		}, bson.M{
+			"$set":   set,
+			"$unset": bson.M{"status": "", "publish_at": "", "rank": "", "rank_version": "", "ranked_at": "", "ranked_engagement": "", "velocity": ""},
+		}).Decode(&item)
+		if err == mongo.ErrNoDocuments {
+			return
+		}
+		if err != nil {
+			bc.log.Error("Unable to publish", zap.String("collection", collection), zap.Error(err))
+			return
+		}
+		bc.log.Info("Published", zap.String("collection", collection), zap.Int("id", item.Id))
+	}
+}
+
+// rankPostsBatch caps how many posts without a current rank get ranked per
+// tick.
+const rankPostsBatch = 1000
+
+// rankWindowEvery is how often the posts in the ranking window are ranked
+// again.
+const rankWindowEvery = 10 * time.Minute
+
+// rankProjection is what ranking a post needs of it.
+var rankProjection = bson.M{
+	"likes": 1, "comments_count": 1, "image": 1, "video": 1, "created_at": 1,
+	"ranked_at": 1, "ranked_engagement": 1, "velocity": 1,
+}
+
+// rankPosts ranks the posts that have no rank, or one computed with other
+// weights, such as posts created before ranking existed, posts that were
+// just published or every post once the weights changed. Every
+// rankWindowEvery it also ranks all posts younger than the ranking window,
+// so that their rank follows their engagement and how fast it grows.
+func (bc *BackgroundController) rankPosts(ct time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	bc.rerank(ctx, bson.M{"rank_version": bson.M{"$ne": models.RankVersion()}}, rankPostsBatch, ct)
+
+	window := models.GetFeedWeights().RankWindow
+	if window <= 0 || ct.Sub(bc.windowRankedAt) < rankWindowEvery {
+		return
+	}
+	bc.windowRankedAt = ct
+	bc.rerank(ctx, bson.M{"created_at": bson.M{"$gte": ct.Add(-window)}}, 0, ct)
+}
+
+// rerank ranks the posts matching filter again, at most limit of them
+// unless it is 0.
+func (bc *BackgroundController) rerank(ctx context.Context, filter bson.M, limit int64, ct time.Time) {
+	c, err := bc.db.Collection("posts").Find(ctx, filter, options.Find().SetProjection(rankProjection).SetLimit(limit))
+	if err != nil {
+		bc.log.Error("Unable to find posts to rank", zap.Error(err))
+		return
+	}
+
+	defer c.Close(ctx)
+
+	for c.Next(ctx) {
+		var p models.Post
+		if err := c.Decode(&p); err != nil {
+			continue
+		}
+		p.Rerank(ct)
+		bc.db.Collection("posts").UpdateOne(ctx, bson.M{"_id": p.Id}, bson.M{"$set": bson.M{
+			"rank":              p.Rank,
+			"rank_version":      p.RankVersion,
+			"ranked_at":         p.RankedAt,
+			"ranked_engagement": p.RankedEngagement,
+			"velocity":          p.Velocity,
+		}})
+	}
+}
+
+// repairTags normalizes the tags of all articles. Older versions stored
+// them as the raw comma separated string when an article was edited, which
+// can't be read back as a list. It reports whether every article was
+// processed.
+func (bc *BackgroundController) repairTags() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	articles := bc.db.Collection("articles")
+	c, err := articles.Find(ctx, bson.M{"tags": bson.M{"$exists": true}}, options.Find().SetProjection(bson.M{"tags": 1}))
+	if err != nil {
+		bc.log.Error("Unable to find article tags", zap.Error(err))
+		return false
+	}
+
+	defer c.Close(ctx)
+
+	repaired := 0
+	for c.Next(ctx) {
+		var a struct {
+			Id   int         `bson:"_id"`
+			Tags interface{} `bson:"tags"`
+		}
+		if err := c.Decode(&a); err != nil {
+			continue
+		}
+
+		var raw []string
+		switch t := a.Tags.(type) {
+		case string:
+			raw = []string{t}
+		case primitive.A:
+			for _, e := range t {
+				if s, ok := e.(string); ok {
+					raw = append(raw, s)
+				}
+			}
+		}
+
+		tags := models.NormalizeTags(strings.Join(raw, ","))
+		_, isString := a.Tags.(string)
+		if !isString && strings.Join(tags, ",") == strings.Join(raw, ",") {
+			continue
+		}
+
+		update := bson.M{"$set": bson.M{"tags": tags}}
+		if len(tags) == 0 {
+			update = bson.M{"$unset": bson.M{"tags": ""}}
+		}
+		// edits made meanwhile already store normalized tags
+		if _, err := articles.UpdateOne(ctx, bson.M{"_id": a.Id, "tags": a.Tags}, update); err != nil {
+			bc.log.Error("Unable to repair tags", zap.Int("id", a.Id), zap.Error(err))
+			return false
+		}
+		repaired++
+	}
+	if err := c.Err(); err != nil {
+		bc.log.Error("Unable to repair tags", zap.Error(err))
+		return false
+	}
+
+	if repaired > 0 {
+		bc.log.Info("Repaired article tags", zap.Int("count", repaired))
+	}
+	return true
+}
+
+// repairPairs stores the pair of users of entries written before pairs
+// were, so that the unique index covers them. Entries duplicating another
+// one of the same pair are removed. It reports whether every entry was
+// processed.
+func (bc *BackgroundController) repairPairs(collection string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	coll := bc.db.Collection(collection)
+	c, err := coll.Find(ctx, bson.M{"pair": bson.M{"$exists": false}}, options.Find().SetProjection(bson.M{"from_id": 1, "to_id": 1}))
+	if err != nil {
+		bc.log.Error("Unable to find entries without pair", zap.String("collection", collection), zap.Error(err))
+		return false
+	}
+
+	defer c.Close(ctx)
+
+	repaired, removed := 0, 0
+	for c.Next(ctx) {
+		var e models.FriendEntry
+		if err := c.Decode(&e); err != nil {
+			continue
+		}
+
+		_, err := coll.UpdateOne(ctx, bson.M{"_id": e.Id}, bson.M{"$set": bson.M{"pair": models.FriendPair(e.FromId, e.ToId)}})
+		if models.IsDuplicateKeyError(err) {
+			_, err = coll.DeleteOne(ctx, bson.M{"_id": e.Id})
+			removed++
+			if err == nil && collection == "friend_requests" {
+				notifyPending(e.ToId)
+			}
+		} else {
+			repaired++
+		}
+		if err != nil {
+			bc.log.Error("Unable to repair pair", zap.String("collection", collection), zap.Int("id", e.Id), zap.Error(err))
+			return false
+		}
+	}
+	if err := c.Err(); err != nil {
+		bc.log.Error("Unable to repair pairs", zap.String("collection", collection), zap.Error(err))
+		return false
+	}
+
+	if repaired > 0 || removed > 0 {
+		bc.log.Info("Repaired pairs", zap.String("collection", collection), zap.Int("count", repaired), zap.Int("removed", removed))
+	}
+	return true
+}
+
+// notifyPending has the notifier tell the user how many friend requests
+// now wait on them.
+func notifyPending(userId int) {
+	if hb := actors.HubById("notifier"); hb != nil {
+		hb.Default(&models.FriendEvent{
+			Kind: models.FriendEventTypePending,
+			ToId: userId,
+		}, nil)
+	}
+}
+
+// buildConversationsBatch caps how many chat messages are moved into
+// conversations per tick.
+const buildConversationsBatch = 500
+
+// buildConversations files chat messages sent before conversations existed
+// into the conversation of their users, oldest first, so that each
+// conversation ends up with its latest message. Nothing is counted as
+// unread. It reports whether every message was filed.
+func (bc *BackgroundController) buildConversations() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	c, err := bc.db.Collection("chats").Find(ctx, bson.M{"conversation_id": bson.M{"$exists": false}}, options.Find().SetSort(bson.M{"_id": 1}).SetLimit(buildConversationsBatch))
+	if err != nil {
+		bc.log.Error("Unable to find chats without conversation", zap.Error(err))
+		return false
+	}
+
+	defer c.Close(ctx)
+
+	built := 0
+	for c.Next(ctx) {
+		var chat models.Chat
+		if err := c.Decode(&chat); err != nil {
+			continue
+		}
+
+		convId, err := ensureConversation(ctx, bc.db, bc.conversationId, chat.FromId, chat.ToId, chat.CreatedAt)
+		if err != nil {
+			bc.log.Error("Unable to find conversation", zap.Int("chat_id", chat.Id), zap.Error(err))
+			return false
+		}
+		chat.ConversationId = convId
+
+		_, err = bc.db.Collection("conversations").UpdateOne(ctx, bson.M{
+			"_id": convId,
+			"$or": []bson.M{
+				{"last_message": bson.M{"$exists": false}},
+				{"last_message._id": bson.M{"$lt": chat.Id}},
+			},
+		}, bson.M{"$set": bson.M{"last_message": chat, "updated_at": chat.CreatedAt}})
+		if err == nil {
+			_, err = bc.db.Collection("chats").UpdateOne(ctx, bson.M{"_id": chat.Id}, bson.M{"$set": bson.M{"conversation_id": convId}})
+		}
+		if err != nil {
+			bc.log.Error("Unable to file chat", zap.Int("chat_id", chat.Id), zap.Error(err))
+			return false
+		}
+		built++
+	}
+	if err := c.Err(); err != nil {
+		bc.log.Error("Unable to build conversations", zap.Error(err))
+		return false
+	}
+
+	if built > 0 {
+		bc.log.Info("Built conversations", zap.Int("chats", built))
+	}
+	return built < buildConversationsBatch
+}
+
+// PdfThumbnailer is the command rendering the first page of a PDF to PNG.
+// It is called the way poppler's pdftoppm is.
+var PdfThumbnailer = "pdftoppm"
+
+// processPdfsBatch caps how many PDFs get processed per tick.
+const processPdfsBatch = 10
+
+// processPdfs reads the page count, size and text of attached PDFs that
+// weren't processed yet, and renders a thumbnail of their first page. The
+// text is kept apart from the article so that it's only used for search.
+func (bc *BackgroundController) processPdfs() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	articles := bc.db.Collection("articles")
+	c, err := articles.Find(ctx, bson.M{
+		"pdf":      bson.M{"$exists": true, "$ne": ""},
+		"pdf_info": bson.M{"$exists": false},
+	}, options.Find().SetProjection(bson.M{"pdf": 1}).SetLimit(processPdfsBatch))
+	if err != nil {
+		bc.log.Error("Unable to find unprocessed PDFs", zap.Error(err))
+		return
+	}
+
+	var pending []models.Article
+	if err := c.All(ctx, &pending); err != nil {
+		bc.log.Error("Unable to find unprocessed PDFs", zap.Error(err))
+		return
+	}
+
+	for _, a := range pending {
+		name := filepath.Base(a.Pdf)
+		info := &models.PdfInfo{File: a.Pdf}
+		set := bson.M{"pdf_info": info}
+
+		// files that can't be read are marked anyway, so they aren't
+		// retried on every tick
+		path := filepath.Join("assets", name)
+		if b, err := os.ReadFile(path); err != nil {
+			bc.log.Warn("Unable to read PDF", zap.Int("id", a.Id), zap.String("pdf", a.Pdf), zap.Error(err))
+		} else if doc, err := models.ParsePdf(b); err != nil {
+			bc.log.Warn("Unable to parse PDF", zap.Int("id", a.Id), zap.String("pdf", a.Pdf), zap.Error(err))
+			info.Size = len(b)
+		} else {
+			info.Size = len(b)
+			info.Pages = doc.Pages
+			info.Thumbnail = bc.pdfThumbnail(ctx, path)
+			set["pdf_text"] = doc.Text
+		}
+
+		// the PDF may have been replaced meanwhile
+		if _, err := articles.UpdateOne(ctx, bson.M{"_id": a.Id, "pdf": a.Pdf}, bson.M{"$set": set}); err != nil {
+			bc.log.Error("Unable to save PDF info", zap.Int("id", a.Id), zap.Error(err))
+		}
+	}
+}
+
+// pdfThumbnail renders the first page of the PDF at path as an asset next
+// to it, and returns the asset's name.
+func (bc *BackgroundController) pdfThumbnail(ctx context.Context, path string) string {
+	if PdfThumbnailer == "" {
+		return ""
+	}
+
+	out := strings.TrimSuffix(path, filepath.Ext(path)) + "_thumb"
+	cmd := exec.CommandContext(ctx, PdfThumbnailer, "-png", "-f", "1", "-l", "1", "-singlefile", "-scale-to", "480", path, out)
+	if b, err := cmd.CombinedOutput(); err != nil {
+		bc.log.Warn("Unable to render PDF thumbnail", zap.String("pdf", path), zap.ByteString("output", b), zap.Error(err))
+		return ""
+	}
+	return filepath.Base(out) + ".png"
+}
+
 // -- end --
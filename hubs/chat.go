@@ -0,0 +1,1197 @@
+package hubs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fr_book_api/actors"
+	"fr_book_api/models"
+	"image"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/thoas/go-funk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// ChatSetup sets up things for the hub.
+func ChatSetup(sugar string, mongoDb *mongo.Database, logger *zap.Logger) error {
+	// -- init --
+	actors.NewHub("chat", &ChatController{
+		db: mongoDb,
+	}, 100, time.Second, logger).Start()
+	return nil
+	// -- end --
+}
+
+// ChatController is controller for the hub.
+type ChatController struct {
+	h   *actors.Hub
+	log *zap.Logger
+	// -- declarations --
+	db             *mongo.Database
+	chatId         *models.IDNode
+	conversationId *models.IDNode
+	// jobs feed the workers that read and write messages
+	jobs []chan chatJob
+	// -- end --
+}
+
+func (cc *ChatController) Connect(h *actors.Hub, ct time.Time) {
+	cc.h = h
+	// -- connect --
+	cc.log = h.Log()
+	cc.chatId, _ = models.NewIDNode(20)
+	cc.conversationId, _ = models.NewIDNode(21)
+	if cc.jobs == nil {
+		for i := 0; i < chatWorkers; i++ {
+			jobs := make(chan chatJob, chatQueue)
+			cc.jobs = append(cc.jobs, jobs)
+			go cc.work(jobs)
+		}
+	}
+	// -- end --
+}
+
+func (cc *ChatController) OnShutdown() {
+	// -- shutdown --
+	// -- end --
+}
+
+func (cc *ChatController) ParseUser(b []byte) (actors.Serializable, error) {
+	// -- parse-user --
+	var ev models.ChatEvent
+
+	err := ev.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ev, nil
+	// -- end --
+}
+
+func (cc *ChatController) ParseHub(b []byte, client actors.HubClient) (actors.Serializable, error) {
+	// -- parse-hub --
+	var ev models.ChatEvent
+
+	err := ev.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ev, nil
+	// -- end --
+}
+
+func (cc *ChatController) ProcessUser(d actors.Serializable, userId int64, ct time.Time) {
+	// -- process-user --
+	ev, ok := d.(*models.ChatEvent)
+	if !ok || ev == nil {
+		cc.log.Error("No data")
+		return
+	}
+
+	id := int(userId)
+	switch ev.Kind {
+	case models.ChatEventTypeDelivered:
+		cc.queue(ev.ChatId, func(out *chatOutbox) {
+			cc.delivered(out, id, ev.ChatId)
+		})
+	case models.ChatEventTypeTyping:
+		cc.queue(ev.ConversationId, func(out *chatOutbox) {
+			cc.typing(out, id, ev.ConversationId)
+		})
+	default:
+		// failures only concern the sender's sessions
+		queued := cc.queue(chatKey(id, ev), func(out *chatOutbox) {
+			if res := cc.change(out, id, ev, ct); res != nil && res.Kind == models.ChatEventTypeError {
+				out.send([]int{id}, res)
+			}
+		})
+		if !queued {
+			cc.h.UserCustom(userId, chatError(ev, errChatBusy.Error()))
+		}
+	}
+	// -- end --
+}
+
+func (cc *ChatController) State(userId int64) []actors.Serializable {
+	// -- state --
+	return nil
+	// -- end --
+}
+
+func (cc *ChatController) ProcessDefault(o actors.Serializable, c *actors.OneTimeClient, ct time.Time) {
+	// -- process-default --
+
+	// the workers hand back what they have for the users' sessions
+	if out, ok := o.(*chatOutbox); ok {
+		out.deliver(cc.h)
+		return
+	}
+
+	ev, ok := o.(*models.ChatEvent)
+	if !ok {
+		return
+	}
+
+	switch ev.Kind {
+	case models.ChatEventTypeSystem:
+		// membership changes are announced by the operations making them,
+		// never by users themselves
+		if ev.Chat != nil {
+			cc.queue(ev.Chat.ConversationId, func(out *chatOutbox) {
+				cc.announce(out, ev.Chat, ct)
+			})
+		}
+	case models.ChatEventTypeRead:
+		// so are read markers, which are moved over REST
+		cc.queue(ev.ConversationId, func(out *chatOutbox) {
+			cc.read(out, ev)
+		})
+	case models.ChatEventTypePreview:
+		// link previews come back here once they're fetched
+		cc.queue(ev.ChatId, func(out *chatOutbox) {
+			cc.preview(out, ev)
+		})
+	case models.ChatEventTypeThumbnails:
+		// and so do image thumbnails once they're made
+		cc.queue(ev.ChatId, func(out *chatOutbox) {
+			cc.thumbnailed(out, ev)
+		})
+	default:
+		// changes made over REST come in here, with the user on the client
+		if c != nil {
+			queued := cc.queue(chatKey(c.UserID, ev), func(out *chatOutbox) {
+				if res := cc.change(out, c.UserID, ev, ct); res != nil {
+					c.Msg(res)
+				}
+			})
+			if !queued {
+				c.Msg(chatError(ev, errChatBusy.Error()))
+			}
+		}
+	}
+	// -- end --
+}
+
+func (cc *ChatController) ProcessHub(d actors.Serializable, c actors.HubClient, ct time.Time) {
+	// -- process-hub --
+	// -- end --
+}
+
+func (cc *ChatController) Tick(ct time.Time) {
+	// -- tick --
+	// -- end --
+}
+
+func (cc *ChatController) OnDisconnect(userId int64) {
+	// -- disconnect --
+	// -- end --
+}
+
+func (cc *ChatController) OnPanic() {
+	// -- panic --
+	// -- end --
+}
+
+func (cc *ChatController) Healthz() string {
+	// -- health --
+	return ""
+	// -- end --
+}
+
+// -- code --
+
+const (
+	// maxChatLength caps the length of a chat message.
+	maxChatLength = 4000
+	// maxEmojiLength caps the length of a reaction, which may be an emoji
+	// made of several code points.
+	maxEmojiLength = 32
+	// maxAttachments caps how many files a message carries.
+	maxAttachments = 10
+	// maxAttachmentName caps the length of an attachment's file name.
+	maxAttachmentName = 255
+	// maxVoiceDuration caps the length of a voice note, in seconds.
+	maxVoiceDuration = 15 * 60
+	// maxWaveform caps how many samples a voice note's waveform has.
+	maxWaveform = 100
+	// thumbnailSize is the largest side of an image thumbnail.
+	thumbnailSize = 320
+	// maxImagePixels caps the size of an attached image, as decoding it
+	// takes four bytes a pixel.
+	maxImagePixels = 40 << 20
+	// thumbnailWorkers caps how many images are decoded at once.
+	thumbnailWorkers = 2
+	// chatWorkers is how many workers read and write messages, and
+	// chatQueue how many changes wait for each.
+	chatWorkers = 4
+	chatQueue   = 100
+	// maxPreviewPage caps how much of a page is read for its preview.
+	maxPreviewPage = 512 << 10
+)
+
+var (
+	imageExts = map[string]bool{"jpg": true, "jpeg": true, "png": true, "gif": true, "bmp": true, "tiff": true}
+	voiceExts = map[string]bool{"m4a": true, "mp3": true, "ogg": true, "oga": true, "opus": true, "wav": true, "aac": true, "webm": true}
+
+	errTooManyAttachments = errors.New("Too many attachments")
+	errAttachmentNotFound = errors.New("Attachment not found")
+	errInvalidImage       = errors.New("Invalid image")
+	errInvalidVoiceNote   = errors.New("Invalid voice note")
+	errNoPreview          = errors.New("Nothing to preview")
+	errPrivateAddress     = errors.New("Address not allowed")
+	errChatBusy           = errors.New("Too many messages, try again")
+
+	thumbnailSlots = make(chan struct{}, thumbnailWorkers)
+)
+
+// ChatEditWindow is how long after sending a message its sender may still
+// edit it.
+var ChatEditWindow = 15 * time.Minute
+
+// chatJob is work for the chat workers. What it has for the users'
+// sessions goes in the outbox.
+type chatJob func(out *chatOutbox)
+
+// chatDelivery is an event for the sessions of some users. Ephemeral
+// events aren't kept for sessions that resume.
+type chatDelivery struct {
+	To        []int             `json:"to"`
+	Event     *models.ChatEvent `json:"event"`
+	Ephemeral bool              `json:"ephemeral"`
+}
+
+// chatOutbox collects what a job has for the users' sessions. Sessions
+// are only reached from the hub loop, so workers hand it back to the hub.
+type chatOutbox struct {
+	Deliveries []*chatDelivery `json:"deliveries"`
+}
+
+func (o *chatOutbox) Serialize() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func (o *chatOutbox) Parse(b []byte) error {
+	return json.Unmarshal(b, o)
+}
+
+func (o *chatOutbox) send(to []int, ev *models.ChatEvent) {
+	o.Deliveries = append(o.Deliveries, &chatDelivery{To: to, Event: ev})
+}
+
+func (o *chatOutbox) sendEphemeral(to []int, ev *models.ChatEvent) {
+	o.Deliveries = append(o.Deliveries, &chatDelivery{To: to, Event: ev, Ephemeral: true})
+}
+
+// deliver sends the events to the sessions. It runs on the hub loop.
+func (o *chatOutbox) deliver(h *actors.Hub) {
+	for _, d := range o.Deliveries {
+		for _, id := range d.To {
+			if d.Ephemeral {
+				h.UserEphemeral(int64(id), d.Event)
+			} else {
+				h.UserCustom(int64(id), d.Event)
+			}
+		}
+	}
+}
+
+// queue hands a job to a worker, keeping the hub loop free of reads and
+// writes. Jobs with the same key, such as the changes to a conversation or
+// to a message, go to the same worker, so they are made in order. It
+// reports whether the job was taken, which it isn't when the worker is
+// behind.
+func (cc *ChatController) queue(key int, job chatJob) bool {
+	if key < 0 {
+		key = -key
+	}
+	select {
+	case cc.jobs[key%len(cc.jobs)] <- job:
+		return true
+	default:
+		cc.log.Warn("Chat worker is behind", zap.Int("key", key))
+		return false
+	}
+}
+
+// work runs jobs, and hands what they have for the users' sessions back
+// to the hub.
+func (cc *ChatController) work(jobs chan chatJob) {
+	for job := range jobs {
+		out := &chatOutbox{}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					cc.log.Error("Chat job failed", zap.Any("panic", r), zap.Stack("stack"))
+				}
+			}()
+			job(out)
+		}()
+		if len(out.Deliveries) > 0 {
+			cc.h.Default(out, nil)
+		}
+	}
+}
+
+// chatKey picks the worker for a change: messages by their conversation,
+// or their two users, and changes to a message by the message.
+func chatKey(userId int, ev *models.ChatEvent) int {
+	if ev.Kind != models.ChatEventTypeSend {
+		return ev.ChatId
+	}
+	if ev.ConversationId != 0 {
+		return ev.ConversationId
+	}
+	return userId + ev.ToId
+}
+
+// change makes the change a user asked for to a conversation and returns
+// what the user gets back, or nil for events users can't send.
+func (cc *ChatController) change(out *chatOutbox, userId int, ev *models.ChatEvent, ct time.Time) *models.ChatEvent {
+	switch ev.Kind {
+	case models.ChatEventTypeSend:
+		return cc.send(out, userId, ev, ct)
+	case models.ChatEventTypeEdit:
+		return cc.edit(out, userId, ev, ct)
+	case models.ChatEventTypeUnsend:
+		return cc.unsend(out, userId, ev)
+	case models.ChatEventTypeDelete:
+		return cc.deleteForMe(out, userId, ev)
+	case models.ChatEventTypeReact:
+		return cc.react(out, userId, ev)
+	}
+	return nil
+}
+
+// send stores a message and hands it to every session of everyone in the
+// conversation, the sender included. Sessions that dropped keep it pending,
+// so they get it when they resume. Messages go either to a conversation or
+// to a user directly. It returns the message event, or an error event when
+// the message can't be sent.
+func (cc *ChatController) send(out *chatOutbox, fromId int, ev *models.ChatEvent, ct time.Time) *models.ChatEvent {
+	content := strings.TrimSpace(ev.Content)
+	if (content == "" && len(ev.Attachments) == 0) || len(content) > maxChatLength {
+		return chatError(ev, "Invalid message")
+	}
+
+	attachments, err := cc.attachments(ev.Attachments)
+	if err != nil {
+		return chatError(ev, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	toId := ev.ToId
+	if ev.ConversationId != 0 {
+		var conv models.Conversation
+		err := cc.db.Collection("conversations").FindOne(ctx, bson.M{"_id": ev.ConversationId, "participant_ids": fromId}).Decode(&conv)
+		if err == mongo.ErrNoDocuments {
+			return chatError(ev, "Conversation not found")
+		}
+		if err != nil {
+			cc.log.Error("Unable to find conversation", zap.Int("conversation_id", ev.ConversationId), zap.Error(err))
+			return chatError(ev, "Unable to send message")
+		}
+
+		if conv.Group {
+			chat := &models.Chat{
+				Id:             int(cc.chatId.Generate().Int64()),
+				ConversationId: conv.Id,
+				FromId:         fromId,
+				Content:        content,
+				Attachments:    attachments,
+				CreatedAt:      ct,
+			}
+			if err := cc.post(ctx, chat); err != nil {
+				cc.log.Error("Unable to store message", zap.Error(err))
+				return chatError(ev, "Unable to send message")
+			}
+			cc.unfurl(chat)
+			cc.thumbnails(chat)
+			return cc.deliver(out, chat, ev.ClientId, conv.ParticipantIds)
+		}
+
+		toId = 0
+		for _, id := range conv.ParticipantIds {
+			if id != fromId {
+				toId = id
+			}
+		}
+	}
+
+	if toId == 0 || toId == fromId {
+		return chatError(ev, "Invalid message")
+	}
+
+	count, err := cc.db.Collection("users").CountDocuments(ctx, bson.M{"_id": toId})
+	if err != nil {
+		cc.log.Error("Unable to find recipient", zap.Int("to_id", toId), zap.Error(err))
+		return chatError(ev, "Unable to send message")
+	}
+	if count == 0 {
+		return chatError(ev, "Recipient not found")
+	}
+
+	blocked, err := cc.db.Collection("blocks").CountDocuments(ctx, bson.M{"$or": []bson.M{
+		{"from_id": fromId, "to_id": toId},
+		{"from_id": toId, "to_id": fromId},
+	}})
+	if err != nil {
+		cc.log.Error("Unable to check blocks", zap.Error(err))
+		return chatError(ev, "Unable to send message")
+	}
+	if blocked > 0 {
+		return chatError(ev, "Recipient not found")
+	}
+
+	convId, err := ensureConversation(ctx, cc.db, cc.conversationId, fromId, toId, ct)
+	if err != nil {
+		cc.log.Error("Unable to find conversation", zap.Error(err))
+		return chatError(ev, "Unable to send message")
+	}
+
+	chat := &models.Chat{
+		Id:             int(cc.chatId.Generate().Int64()),
+		ConversationId: convId,
+		FromId:         fromId,
+		ToId:           toId,
+		Content:        content,
+		Attachments:    attachments,
+		CreatedAt:      ct,
+	}
+	if err := cc.post(ctx, chat); err != nil {
+		cc.log.Error("Unable to store message", zap.Error(err))
+		return chatError(ev, "Unable to send message")
+	}
+
+	// chatting lifts each other's posts in the ranked feed
+	for _, p := range [][2]int{{fromId, toId}, {toId, fromId}} {
+		cc.db.Collection("affinities").UpdateOne(ctx, bson.M{
+			"user_id":  p[0],
+			"other_id": p[1],
+		}, bson.M{"$inc": bson.M{"chats": 1}}, options.Update().SetUpsert(true))
+	}
+
+	cc.unfurl(chat)
+	cc.thumbnails(chat)
+	return cc.deliver(out, chat, ev.ClientId, []int{fromId, toId})
+}
+
+// announce stores a system message about a change to a group and hands it
+// to everyone in the group, and to the member it is about, who may just
+// have left.
+func (cc *ChatController) announce(out *chatOutbox, chat *models.Chat, ct time.Time) {
+	if chat == nil || chat.ConversationId == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var conv models.Conversation
+	if err := cc.db.Collection("conversations").FindOne(ctx, bson.M{"_id": chat.ConversationId}).Decode(&conv); err != nil {
+		cc.log.Error("Unable to find conversation", zap.Int("conversation_id", chat.ConversationId), zap.Error(err))
+		return
+	}
+
+	chat.Id = int(cc.chatId.Generate().Int64())
+	chat.CreatedAt = ct
+
+	to := conv.ParticipantIds
+	if chat.TargetId != 0 && !funk.ContainsInt(to, chat.TargetId) {
+		to = append(to, chat.TargetId)
+	}
+	if err := cc.post(ctx, chat); err != nil {
+		cc.log.Error("Unable to store system message", zap.Error(err))
+		return
+	}
+	cc.deliver(out, chat, "", to)
+}
+
+// post stores a message and makes it the latest of its conversation. A
+// text message counts as unread for everyone but its sender, for whom it
+// reads everything before it.
+func (cc *ChatController) post(ctx context.Context, chat *models.Chat) error {
+	if _, err := cc.db.Collection("chats").InsertOne(ctx, chat); err != nil {
+		return err
+	}
+
+	// messages of a conversation may be stored by different workers, so a
+	// message only becomes the latest over an older one
+	_, err := cc.db.Collection("conversations").UpdateOne(ctx, bson.M{
+		"_id": chat.ConversationId,
+		"$or": []bson.M{
+			{"last_message": bson.M{"$exists": false}},
+			{"last_message._id": bson.M{"$lt": chat.Id}},
+		},
+	}, bson.M{"$set": bson.M{
+		"last_message": chat,
+		"updated_at":   chat.CreatedAt,
+	}})
+	if err != nil {
+		cc.log.Error("Unable to update conversation", zap.Int("conversation_id", chat.ConversationId), zap.Error(err))
+	}
+	if chat.Kind != models.ChatKindText {
+		return nil
+	}
+
+	_, err = cc.db.Collection("conversations").UpdateOne(ctx, bson.M{"_id": chat.ConversationId}, bson.M{
+		"$set": bson.M{
+			"members.$[from].last_read_id": chat.Id,
+			"members.$[from].unread_count": 0,
+		},
+		"$inc": bson.M{"members.$[to].unread_count": 1},
+	}, options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"from.user_id": chat.FromId},
+		bson.M{"to.user_id": bson.M{"$ne": chat.FromId}},
+	}}))
+	if err != nil {
+		cc.log.Error("Unable to count unread messages", zap.Int("conversation_id", chat.ConversationId), zap.Error(err))
+	}
+	return nil
+}
+
+// deliver hands a stored message to every session of the given users. The
+// client id lets the sending session match the message to the one it sent.
+func (cc *ChatController) deliver(out *chatOutbox, chat *models.Chat, clientId string, to []int) *models.ChatEvent {
+	msg := &models.ChatEvent{
+		Kind:     models.ChatEventTypeMessage,
+		ClientId: clientId,
+		Chat:     chat,
+	}
+	out.send(to, msg)
+	return msg
+}
+
+// edit replaces the content of a message the user sent, as long as the
+// edit window is open.
+func (cc *ChatController) edit(out *chatOutbox, userId int, ev *models.ChatEvent, ct time.Time) *models.ChatEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	chat, to, res := cc.findChat(ctx, userId, ev)
+	if res != nil {
+		return res
+	}
+
+	// attachments stay as they are, and may stand on their own
+	content := strings.TrimSpace(ev.Content)
+	if (content == "" && len(chat.Attachments) == 0) || len(content) > maxChatLength {
+		return chatError(ev, "Invalid message")
+	}
+	if chat.FromId != userId || chat.Kind != models.ChatKindText || chat.Unsent {
+		return chatError(ev, "Message can't be edited")
+	}
+	if ct.Sub(chat.CreatedAt) > ChatEditWindow {
+		return chatError(ev, "Message can no longer be edited")
+	}
+
+	update := bson.M{"$set": bson.M{"content": content, "edited_at": ct}}
+	if models.FirstUrl(content) == models.FirstUrl(chat.Content) {
+		return cc.update(out, ctx, ev, chat.Id, update, to)
+	}
+
+	// a different link gets a new preview
+	update["$unset"] = bson.M{"link_preview": ""}
+	res = cc.update(out, ctx, ev, chat.Id, update, to)
+	if res.Chat != nil {
+		cc.unfurl(res.Chat)
+	}
+	return res
+}
+
+// unsend takes back a message the user sent for everyone. What's left is
+// a tombstone, so that the conversation shows a message was there.
+func (cc *ChatController) unsend(out *chatOutbox, userId int, ev *models.ChatEvent) *models.ChatEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	chat, to, res := cc.findChat(ctx, userId, ev)
+	if res != nil {
+		return res
+	}
+	if chat.FromId != userId || chat.Kind != models.ChatKindText {
+		return chatError(ev, "Message can't be unsent")
+	}
+
+	return cc.update(out, ctx, ev, chat.Id, bson.M{
+		"$set":   bson.M{"content": "", "unsent": true},
+		"$unset": bson.M{"edited_at": "", "reactions": "", "attachments": "", "link_preview": ""},
+	}, to)
+}
+
+// deleteForMe hides a message from the user only. Their other sessions are
+// told to drop it too.
+func (cc *ChatController) deleteForMe(out *chatOutbox, userId int, ev *models.ChatEvent) *models.ChatEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	chat, _, res := cc.findChat(ctx, userId, ev)
+	if res != nil {
+		return res
+	}
+
+	if _, err := cc.db.Collection("chats").UpdateOne(ctx, bson.M{"_id": chat.Id}, bson.M{"$addToSet": bson.M{"deleted_for": userId}}); err != nil {
+		cc.log.Error("Unable to delete message", zap.Int("chat_id", chat.Id), zap.Error(err))
+		return chatError(ev, "Unable to delete message")
+	}
+
+	msg := &models.ChatEvent{
+		Kind:           models.ChatEventTypeDeleted,
+		ClientId:       ev.ClientId,
+		ChatId:         chat.Id,
+		ConversationId: chat.ConversationId,
+	}
+	out.send([]int{userId}, msg)
+	return msg
+}
+
+// react sets the user's reaction to a message, replacing the one they had.
+// An empty emoji takes the reaction back.
+func (cc *ChatController) react(out *chatOutbox, userId int, ev *models.ChatEvent) *models.ChatEvent {
+	emoji := strings.TrimSpace(ev.Emoji)
+	if len(emoji) > maxEmojiLength || strings.ContainsAny(emoji, " \t\n") {
+		return chatError(ev, "Invalid reaction")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	chat, to, res := cc.findChat(ctx, userId, ev)
+	if res != nil {
+		return res
+	}
+	if chat.Kind != models.ChatKindText || chat.Unsent {
+		return chatError(ev, "Message can't be reacted to")
+	}
+
+	// the user's reaction is dropped and the new one added in one update
+	reactions := bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$reactions", bson.A{}}},
+		"cond":  bson.M{"$ne": bson.A{"$$this.user_id", userId}},
+	}}
+	if emoji != "" {
+		reactions = bson.M{"$concatArrays": bson.A{reactions, bson.A{&models.ChatReaction{
+			UserId: userId,
+			Emoji:  emoji,
+		}}}}
+	}
+	return cc.update(out, ctx, ev, chat.Id, bson.A{bson.M{"$set": bson.M{"reactions": reactions}}}, to)
+}
+
+// findChat looks up the message an event is about, along with everyone in
+// its conversation. The message has to be one the user can see.
+func (cc *ChatController) findChat(ctx context.Context, userId int, ev *models.ChatEvent) (*models.Chat, []int, *models.ChatEvent) {
+	var chat models.Chat
+	err := cc.db.Collection("chats").FindOne(ctx, bson.M{"_id": ev.ChatId, "deleted_for": bson.M{"$ne": userId}}).Decode(&chat)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil, chatError(ev, "Message not found")
+	}
+	if err != nil {
+		cc.log.Error("Unable to find message", zap.Int("chat_id", ev.ChatId), zap.Error(err))
+		return nil, nil, chatError(ev, "Unable to find message")
+	}
+
+	if chat.ToId != 0 {
+		if chat.FromId != userId && chat.ToId != userId {
+			return nil, nil, chatError(ev, "Message not found")
+		}
+		return &chat, []int{chat.FromId, chat.ToId}, nil
+	}
+
+	to, ok := cc.participants(ctx, chat.ConversationId, userId)
+	if !ok {
+		return nil, nil, chatError(ev, "Message not found")
+	}
+	return &chat, to, nil
+}
+
+// update changes a message, keeps the preview of its conversation in step
+// and hands the changed message to everyone in the conversation. The
+// update is a document or a pipeline.
+func (cc *ChatController) update(out *chatOutbox, ctx context.Context, ev *models.ChatEvent, chatId int, update interface{}, to []int) *models.ChatEvent {
+	var chat models.Chat
+	err := cc.db.Collection("chats").FindOneAndUpdate(ctx, bson.M{"_id": chatId}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&chat)
+	if err != nil {
+		cc.log.Error("Unable to update message", zap.Int("chat_id", chatId), zap.Error(err))
+		return chatError(ev, "Unable to update message")
+	}
+	chat.DeletedFor = nil
+
+	_, err = cc.db.Collection("conversations").UpdateOne(ctx, bson.M{
+		"_id":              chat.ConversationId,
+		"last_message._id": chat.Id,
+	}, bson.M{"$set": bson.M{"last_message": &chat}})
+	if err != nil {
+		cc.log.Error("Unable to update conversation", zap.Int("conversation_id", chat.ConversationId), zap.Error(err))
+	}
+
+	msg := &models.ChatEvent{
+		Kind:     models.ChatEventTypeUpdated,
+		ClientId: ev.ClientId,
+		Chat:     &chat,
+	}
+	out.send(to, msg)
+	return msg
+}
+
+// attachments checks that every attachment of a message is an uploaded
+// file, and describes it from the file itself: images get their size from
+// their header, voice notes keep the duration and waveform recorded by the
+// client. Images are only decoded later, by thumbnails.
+func (cc *ChatController) attachments(in []*models.ChatAttachment) ([]*models.ChatAttachment, error) {
+	if len(in) > maxAttachments {
+		return nil, errTooManyAttachments
+	}
+
+	var out []*models.ChatAttachment
+	for _, a := range in {
+		if a == nil {
+			return nil, errAttachmentNotFound
+		}
+		name := a.File
+		if u, err := url.Parse(name); err == nil {
+			name = u.Path
+		}
+		name = filepath.Base(name)
+		path := filepath.Join("assets", name)
+		st, err := os.Stat(path)
+		if name == "." || name == "/" || err != nil || st.IsDir() {
+			return nil, errAttachmentNotFound
+		}
+
+		att := &models.ChatAttachment{
+			Type: models.AttachmentTypeFile,
+			File: name,
+			Name: strings.TrimSpace(a.Name),
+			Size: int(st.Size()),
+		}
+		if len(att.Name) > maxAttachmentName {
+			att.Name = strings.ToValidUTF8(att.Name[:maxAttachmentName], "")
+		}
+
+		ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+		switch {
+		case imageExts[ext]:
+			cfg, err := imageConfig(path)
+			if err != nil || cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+				return nil, errInvalidImage
+			}
+			att.Type = models.AttachmentTypeImage
+			att.Width = cfg.Width
+			att.Height = cfg.Height
+
+		case voiceExts[ext]:
+			if a.Duration <= 0 || a.Duration > maxVoiceDuration || len(a.Waveform) > maxWaveform {
+				return nil, errInvalidVoiceNote
+			}
+			for _, v := range a.Waveform {
+				if v < 0 || v > 255 {
+					return nil, errInvalidVoiceNote
+				}
+			}
+			att.Type = models.AttachmentTypeVoice
+			att.Duration = a.Duration
+			att.Waveform = a.Waveform
+		}
+		out = append(out, att)
+	}
+	return out, nil
+}
+
+// thumbnails makes the thumbnails of the images of a message in the
+// background, and hands them back to the hub when they're there, along with
+// the size of the images as they are shown once rotated.
+func (cc *ChatController) thumbnails(chat *models.Chat) {
+	var attachments []*models.ChatAttachment
+	images := 0
+	for _, a := range chat.Attachments {
+		att := *a
+		attachments = append(attachments, &att)
+		if a.Type == models.AttachmentTypeImage {
+			images++
+		}
+	}
+	if images == 0 {
+		return
+	}
+	chatId := chat.Id
+	go func() {
+		thumbnailSlots <- struct{}{}
+		defer func() { <-thumbnailSlots }()
+
+		for _, a := range attachments {
+			if a.Type != models.AttachmentTypeImage {
+				continue
+			}
+			path := filepath.Join("assets", a.File)
+			img, err := imaging.Open(path, imaging.AutoOrientation(true))
+			if err != nil {
+				cc.log.Warn("Unable to open image", zap.String("image", path), zap.Error(err))
+				continue
+			}
+			a.Width = img.Bounds().Dx()
+			a.Height = img.Bounds().Dy()
+			a.Thumbnail = cc.thumbnail(path, img)
+		}
+		cc.h.Default(&models.ChatEvent{
+			Kind:   models.ChatEventTypeThumbnails,
+			ChatId: chatId,
+			Chat:   &models.Chat{Attachments: attachments},
+		}, nil)
+	}()
+}
+
+// thumbnailed stores the thumbnails made for the images of a message,
+// unless the message was unsent meanwhile.
+func (cc *ChatController) thumbnailed(out *chatOutbox, ev *models.ChatEvent) {
+	if ev.Chat == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var chat models.Chat
+	if err := cc.db.Collection("chats").FindOne(ctx, bson.M{"_id": ev.ChatId}).Decode(&chat); err != nil {
+		return
+	}
+	if chat.Unsent || len(chat.Attachments) != len(ev.Chat.Attachments) {
+		return
+	}
+
+	set := bson.M{}
+	for i, a := range ev.Chat.Attachments {
+		if a.Thumbnail == "" || chat.Attachments[i].File != a.File {
+			continue
+		}
+		field := "attachments." + strconv.Itoa(i) + "."
+		set[field+"thumbnail"] = a.Thumbnail
+		set[field+"width"] = a.Width
+		set[field+"height"] = a.Height
+	}
+	if len(set) == 0 {
+		return
+	}
+
+	to := []int{chat.FromId, chat.ToId}
+	if chat.ToId == 0 {
+		var ok bool
+		if to, ok = cc.participants(ctx, chat.ConversationId, chat.FromId); !ok {
+			return
+		}
+	}
+	cc.update(out, ctx, ev, chat.Id, bson.M{"$set": set}, to)
+}
+
+// thumbnail scales an image down to fit a chat bubble, as an asset next to
+// it, and returns the asset's name. Assets are named by their content, so
+// a thumbnail made before is reused.
+func (cc *ChatController) thumbnail(path string, img image.Image) string {
+	thumb := strings.TrimSuffix(path, filepath.Ext(path)) + "_thumb" + filepath.Ext(path)
+	if _, err := os.Stat(thumb); err == nil {
+		return filepath.Base(thumb)
+	}
+	if err := imaging.Save(imaging.Fit(img, thumbnailSize, thumbnailSize, imaging.Lanczos), thumb); err != nil {
+		cc.log.Warn("Unable to save thumbnail", zap.String("image", path), zap.Error(err))
+		return ""
+	}
+	return filepath.Base(thumb)
+}
+
+// imageConfig reads the size of an image from its header.
+func imageConfig(path string) (image.Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	return cfg, err
+}
+
+// unfurl fetches the preview of the first link in a message in the
+// background, and hands it back to the hub when it's there.
+func (cc *ChatController) unfurl(chat *models.Chat) {
+	link := models.FirstUrl(chat.Content)
+	if link == "" {
+		return
+	}
+	chatId := chat.Id
+	go func() {
+		p, err := fetchLinkPreview(link)
+		if err != nil {
+			cc.log.Debug("No link preview", zap.String("url", link), zap.Error(err))
+			return
+		}
+		cc.h.Default(&models.ChatEvent{
+			Kind:   models.ChatEventTypePreview,
+			ChatId: chatId,
+			Chat:   &models.Chat{LinkPreview: p},
+		}, nil)
+	}()
+}
+
+// preview stores a fetched link preview with its message, unless the link
+// was edited away or the message unsent meanwhile.
+func (cc *ChatController) preview(out *chatOutbox, ev *models.ChatEvent) {
+	if ev.Chat == nil || ev.Chat.LinkPreview == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var chat models.Chat
+	if err := cc.db.Collection("chats").FindOne(ctx, bson.M{"_id": ev.ChatId}).Decode(&chat); err != nil {
+		return
+	}
+	if chat.Unsent || models.FirstUrl(chat.Content) != ev.Chat.LinkPreview.Url {
+		return
+	}
+
+	to := []int{chat.FromId, chat.ToId}
+	if chat.ToId == 0 {
+		var ok bool
+		if to, ok = cc.participants(ctx, chat.ConversationId, chat.FromId); !ok {
+			return
+		}
+	}
+	cc.update(out, ctx, ev, chat.Id, bson.M{"$set": bson.M{"link_preview": ev.Chat.LinkPreview}}, to)
+}
+
+// delivered records that a message reached one of the recipient's
+// sessions, and tells its sender. Clients acknowledge every message they
+// didn't send as soon as they get it.
+func (cc *ChatController) delivered(out *chatOutbox, userId, chatId int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var chat models.Chat
+	err := cc.db.Collection("chats").FindOne(ctx, bson.M{"_id": chatId}, options.FindOne().SetProjection(bson.M{
+		"conversation_id": 1,
+		"from_id":         1,
+		"kind":            1,
+		"to_id":           1,
+	})).Decode(&chat)
+	if err != nil || chat.FromId == userId || chat.Kind != models.ChatKindText {
+		return
+	}
+	if chat.ToId != userId {
+		if _, ok := cc.participants(ctx, chat.ConversationId, userId); !ok {
+			return
+		}
+	}
+
+	res, err := cc.db.Collection("chats").UpdateOne(ctx, bson.M{"_id": chatId}, bson.M{"$addToSet": bson.M{"delivered_ids": userId}})
+	if err != nil {
+		cc.log.Error("Unable to mark message delivered", zap.Int("chat_id", chatId), zap.Error(err))
+		return
+	}
+	if res.ModifiedCount == 0 {
+		return
+	}
+
+	out.send([]int{chat.FromId}, &models.ChatEvent{
+		Kind:           models.ChatEventTypeDelivered,
+		ChatId:         chatId,
+		ConversationId: chat.ConversationId,
+		UserId:         userId,
+	})
+}
+
+// read tells everyone in a conversation how far one of them has read, the
+// reader's other sessions included.
+func (cc *ChatController) read(out *chatOutbox, ev *models.ChatEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	participants, ok := cc.participants(ctx, ev.ConversationId, ev.UserId)
+	if !ok {
+		return
+	}
+	out.send(participants, ev)
+}
+
+// typing tells everyone else in a conversation that the user is typing.
+// It's only worth anything right away, so sessions that resume don't get
+// it; clients drop the indicator after a few seconds without another one.
+func (cc *ChatController) typing(out *chatOutbox, userId, conversationId int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	participants, ok := cc.participants(ctx, conversationId, userId)
+	if !ok {
+		return
+	}
+
+	ev := &models.ChatEvent{
+		Kind:           models.ChatEventTypeTyping,
+		ConversationId: conversationId,
+		UserId:         userId,
+	}
+	var others []int
+	for _, id := range participants {
+		if id != userId {
+			others = append(others, id)
+		}
+	}
+	out.sendEphemeral(others, ev)
+}
+
+// participants returns who is in the conversation, if the user is.
+func (cc *ChatController) participants(ctx context.Context, conversationId, userId int) ([]int, bool) {
+	var conv models.Conversation
+	err := cc.db.Collection("conversations").FindOne(ctx, bson.M{"_id": conversationId, "participant_ids": userId},
+		options.FindOne().SetProjection(bson.M{"participant_ids": 1})).Decode(&conv)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			cc.log.Error("Unable to find conversation", zap.Int("conversation_id", conversationId), zap.Error(err))
+		}
+		return nil, false
+	}
+	return conv.ParticipantIds, true
+}
+
+// ensureConversation returns the id of the conversation between two users,
+// creating it when they never talked before.
+func ensureConversation(ctx context.Context, db *mongo.Database, node *models.IDNode, a, b int, ct time.Time) (int, error) {
+	filter := bson.M{"pair": models.FriendPair(a, b)}
+	update := bson.M{"$setOnInsert": bson.M{
+		"_id":             int(node.Generate().Int64()),
+		"participant_ids": []int{a, b},
+		"members": []*models.ConversationMember{
+			{UserId: a, JoinedAt: &ct},
+			{UserId: b, JoinedAt: &ct},
+		},
+		"created_at": ct,
+		"updated_at": ct,
+	}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After).SetProjection(bson.M{"_id": 1})
+
+	var conv models.Conversation
+	err := db.Collection("conversations").FindOneAndUpdate(ctx, filter, update, opts).Decode(&conv)
+	if models.IsDuplicateKeyError(err) {
+		// created by someone else in the meantime
+		err = db.Collection("conversations").FindOne(ctx, filter).Decode(&conv)
+	}
+	return conv.Id, err
+}
+
+func chatError(ev *models.ChatEvent, msg string) *models.ChatEvent {
+	return &models.ChatEvent{
+		Kind:     models.ChatEventTypeError,
+		ClientId: ev.ClientId,
+		Error:    msg,
+	}
+}
+
+// LinkPreviewUrl, when set, is fetched instead of linked pages, with the
+// page's address in the url query parameter. It points link previews at an
+// unfurling service, or at a local stand-in.
+var LinkPreviewUrl = ""
+
+// linkPreviewTimeout caps how long fetching a preview may take.
+const linkPreviewTimeout = 5 * time.Second
+
+var (
+	// linkClient fetches pages for previews. Users pick what it fetches,
+	// so it only reaches public addresses.
+	linkClient = &http.Client{
+		Timeout: linkPreviewTimeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{Timeout: linkPreviewTimeout, Control: publicAddress}).DialContext,
+		},
+	}
+	// standInClient fetches previews from LinkPreviewUrl.
+	standInClient = &http.Client{Timeout: linkPreviewTimeout}
+)
+
+// fetchLinkPreview fetches the page a link points to and reads its
+// preview.
+func fetchLinkPreview(link string) (*models.LinkPreview, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errNoPreview
+	}
+
+	target, client := link, linkClient
+	if LinkPreviewUrl != "" {
+		sep := "?"
+		if strings.Contains(LinkPreviewUrl, "?") {
+			sep = "&"
+		}
+		target, client = LinkPreviewUrl+sep+"url="+url.QueryEscape(link), standInClient
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return nil, errNoPreview
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxPreviewPage))
+	if err != nil {
+		return nil, err
+	}
+
+	// relative images are relative to where redirects ended up
+	base := u
+	if LinkPreviewUrl == "" {
+		base = resp.Request.URL
+	}
+	p := models.ParseLinkPreview(base, b)
+	if p == nil {
+		return nil, errNoPreview
+	}
+	p.Url = link
+	return p, nil
+}
+
+// publicAddress refuses connections to loopback, private and link-local
+// addresses.
+func publicAddress(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+// -- end --
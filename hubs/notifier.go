@@ -2,6 +2,7 @@ package hubs
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"fr_book_api/actors"
 	"fr_book_api/models"
@@ -33,6 +34,9 @@ type NotifierController struct {
 	db         *mongo.Database
 	chId       *models.IDNode
 	callStates []*callCurr
+	// pending holds the friend events waiting on a count of the user's
+	// pending requests. Users are only in it while a count runs.
+	pending map[int]*pendingQueue
 	// -- end --
 }
 
@@ -41,6 +45,7 @@ func (nc *NotifierController) Connect(h *actors.Hub, ct time.Time) {
 	// -- connect --
 	nc.log = h.Log()
 	nc.chId, _ = models.NewIDNode(6)
+	nc.pending = make(map[int]*pendingQueue)
 	// -- end --
 }
 
@@ -196,6 +201,12 @@ func (nc *NotifierController) ProcessUser(d actors.Serializable, userId int64, c
 
 func (nc *NotifierController) State(userId int64) []actors.Serializable {
 	// -- state --
+	// (re)connecting clients learn how many friend requests wait on them,
+	// once they are counted
+	nc.queuePending(&models.FriendEvent{
+		Kind: models.FriendEventTypePending,
+		ToId: int(userId),
+	})
 	return nil
 	// -- end --
 }
@@ -203,6 +214,21 @@ func (nc *NotifierController) State(userId int64) []actors.Serializable {
 func (nc *NotifierController) ProcessDefault(o actors.Serializable, c *actors.OneTimeClient, ct time.Time) {
 	// -- process-default --
 
+	// friend events come from the operations, addressed to the user they
+	// concern, and only reach them while connected. They go out once the
+	// user's pending requests are counted, carrying the count.
+	if ev, ok := o.(*models.FriendEvent); ok {
+		if nc.h.HasUser(int64(ev.ToId)) {
+			nc.queuePending(ev)
+		}
+		return
+	}
+
+	if p, ok := o.(*pendingCount); ok {
+		nc.sendPending(p)
+		return
+	}
+
 	fmt.Println("Processing Default")
 	// -- end --
 }
@@ -254,6 +280,96 @@ func (nc *NotifierController) Healthz() string {
 
 // -- code --
 
+// pendingQueue holds a user's friend events in the order they came. The
+// first covered ones go out with the count running for them.
+type pendingQueue struct {
+	events  []*models.FriendEvent
+	covered int
+}
+
+// pendingCount is how many friend requests wait on a user, as counted
+// outside the hub, or -1 if they couldn't be counted.
+type pendingCount struct {
+	UserId int `json:"user_id"`
+	Count  int `json:"count"`
+}
+
+func (p *pendingCount) Serialize() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *pendingCount) Parse(b []byte) error {
+	return json.Unmarshal(b, p)
+}
+
+// queuePending queues a friend event until the pending requests of its
+// user are counted. A single count runs per user at a time, so counts
+// can't arrive out of order, and the events that come while it runs wait
+// for the next one.
+func (nc *NotifierController) queuePending(ev *models.FriendEvent) {
+	q, ok := nc.pending[ev.ToId]
+	if !ok {
+		q = &pendingQueue{}
+		nc.pending[ev.ToId] = q
+	}
+	q.events = append(q.events, ev)
+	if !ok {
+		nc.countPending(ev.ToId, q)
+	}
+}
+
+// countPending counts the friend requests waiting on a user in the
+// background, and hands the count back to the hub.
+func (nc *NotifierController) countPending(userId int, q *pendingQueue) {
+	q.covered = len(q.events)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		count, err := nc.db.Collection("friend_requests").CountDocuments(ctx, bson.M{"to_id": userId})
+		if err != nil {
+			nc.log.Error("Unable to count friend requests", zap.Int("user_id", userId), zap.Error(err))
+			count = -1
+		}
+		nc.h.Default(&pendingCount{UserId: userId, Count: int(count)}, nil)
+	}()
+}
+
+// sendPending sends the events a count was running for. Events that only
+// asked for the count go out as one, unless others carry it already.
+func (nc *NotifierController) sendPending(p *pendingCount) {
+	q, ok := nc.pending[p.UserId]
+	if !ok {
+		return
+	}
+	ready := q.events[:q.covered]
+	q.events = q.events[q.covered:]
+	if len(q.events) > 0 {
+		nc.countPending(p.UserId, q)
+	} else {
+		delete(nc.pending, p.UserId)
+	}
+
+	// the events are dropped if the user left or the count failed
+	if p.Count < 0 || !nc.h.HasUser(int64(p.UserId)) {
+		return
+	}
+	var update *models.FriendEvent
+	sent := false
+	for _, ev := range ready {
+		ev.PendingCount = p.Count
+		if ev.Kind == models.FriendEventTypePending {
+			update = ev
+			continue
+		}
+		nc.h.UserCustom(int64(p.UserId), ev)
+		sent = true
+	}
+	if update != nil && !sent {
+		nc.h.UserCustom(int64(p.UserId), update)
+	}
+}
+
 // create a unique id using 2 integers
 func (nc *NotifierController) GetChannelId() string {
 	// a and b can be swapped and still create the same id
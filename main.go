@@ -25,6 +25,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	// -- imports --
+	"fr_book_api/models"
 	// -- end --
 )
 
@@ -33,6 +34,17 @@ type MongoOptions struct {
 	Database string `long:"database" description:"Which MongoDatabse to connect to" default:"frbook"`
 }
 
+type FeedOptions struct {
+	RecencyHalfLife time.Duration `long:"recency_half_life" description:"Age at which a post counts half in the ranked feed" default:"24h"`
+	Engagement      float64       `long:"engagement" description:"Weight of likes and comments in the ranked feed" default:"1"`
+	Comment         float64       `long:"comment" description:"How many likes a comment is worth" default:"2"`
+	Velocity        float64       `long:"velocity" description:"Weight of the likes and comments a post gains per hour in the ranked feed" default:"0.5"`
+	RankWindow      time.Duration `long:"rank_window" description:"Age until which posts are ranked again as their engagement grows" default:"72h"`
+	Media           float64       `long:"media" description:"Ranked feed bonus for posts with a video, half for images" default:"0.3"`
+	Affinity        float64       `long:"affinity" description:"Weight of chats and interactions with the author in the ranked feed" default:"1"`
+	Candidates      int           `long:"candidates" description:"How many top ranked posts are scored per feed request" default:"300"`
+}
+
 type Options struct {
 	Host  string `short:"h" long:"host" description:"What host" default:""`
 	Port  int    `short:"p" long:"port" description:"Enter port to run the server on" default:"8000"`
@@ -45,6 +57,10 @@ type Options struct {
 	UploadBucket     string `long:"upload_bucket" `
 
 	// -- options --
+	Feed           *FeedOptions  `group:"feed" namespace:"feed"`
+	PdfThumbnailer string        `long:"pdf_thumbnailer" description:"Command rendering the first page of a PDF to PNG, with pdftoppm's arguments" default:"pdftoppm"`
+	ChatEditWindow time.Duration `long:"chat_edit_window" description:"How long after sending a chat message it may still be edited" default:"15m"`
+	LinkPreviewUrl string        `long:"link_preview_url" description:"Fetch link previews from this URL, with the link in the url query parameter, instead of from the linked pages"`
 	// -- end --
 }
 
@@ -80,6 +96,19 @@ func main() {
 	}
 
 	// -- cache-init --
+	models.SetFeedWeights(models.FeedWeights{
+		RecencyHalfLife: opts.Feed.RecencyHalfLife,
+		Engagement:      opts.Feed.Engagement,
+		Comment:         opts.Feed.Comment,
+		Velocity:        opts.Feed.Velocity,
+		RankWindow:      opts.Feed.RankWindow,
+		Media:           opts.Feed.Media,
+		Affinity:        opts.Feed.Affinity,
+		Candidates:      opts.Feed.Candidates,
+	})
+	hubs.PdfThumbnailer = opts.PdfThumbnailer
+	hubs.ChatEditWindow = opts.ChatEditWindow
+	hubs.LinkPreviewUrl = opts.LinkPreviewUrl
 	// -- end --
 
 	if err := hubs.CallNotifierSetup(opts.Sugar, mongoDb, logger); err != nil {
@@ -94,35 +123,93 @@ func main() {
 	if err := hubs.BackgroundSetup(opts.Sugar, mongoDb, logger); err != nil {
 		panic(err.Error())
 	}
+	if err := hubs.ChatSetup(opts.Sugar, mongoDb, logger); err != nil {
+		panic(err.Error())
+	}
 
 	r := mux.NewRouter()
 	r.Handle("/add-chat", operations.AddChat(opts.Sugar, mongoDb, logger)).Methods("POST")
 	r.Handle("/articles", operations.GetArticles(opts.Sugar, mongoDb, logger)).Methods("GET")
 	r.Handle("/articles", operations.CreateArticle(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/articles/drafts", operations.GetArticleDrafts(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/articles/feed.{format:atom|rss|json}", operations.GetArticleFeed(mongoDb, logger)).Methods("GET")
+	r.Handle("/articles/review", operations.GetArticleReviews(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/articles/tags", operations.GetArticleTags(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/articles/tags/{tag}/feed.{format:atom|rss|json}", operations.GetTagFeed(mongoDb, logger)).Methods("GET")
 	r.Handle("/articles/{id}", operations.GetArticle(opts.Sugar, mongoDb, logger)).Methods("GET")
 	r.Handle("/articles/{id}", operations.UpdateArticle(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/articles/{id}/read", operations.ReadArticle(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/articles/{id}/related", operations.GetRelatedArticles(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/articles/{id}/revisions", operations.GetArticleRevisions(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/articles/{id}/revisions/diff", operations.GetArticleDiff(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/articles/{id}/revisions/{revision}/restore", operations.RestoreArticleRevision(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/articles/{id}/stats", operations.GetArticleStats(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/articles/{id}/status", operations.SetArticleStatus(opts.Sugar, mongoDb, logger)).Methods("POST")
 	r.Handle("/assets/{name}", operations.GetAsset(mongoDb, logger)).Methods("GET")
+	r.Handle("/bookmark-collections", operations.GetBookmarkCollections(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/bookmarks", operations.GetBookmarks(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/bookmarks", operations.SaveBookmark(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/bookmarks/unsave", operations.UnsaveBookmark(opts.Sugar, mongoDb, logger)).Methods("POST")
 	r.Handle("/chats", operations.GetChats(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/chats/{id}", operations.EditChat(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/chats/{id}", operations.DeleteChat(opts.Sugar, mongoDb, logger)).Methods("DELETE")
+	r.Handle("/chats/{id}/react", operations.ReactToChat(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/chats/{id}/unsend", operations.UnsendChat(opts.Sugar, mongoDb, logger)).Methods("POST")
 	r.Handle("/complete-registration", operations.CompleteRegistration(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/conversations", operations.GetConversations(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/conversations", operations.CreateConversation(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/conversations/read", operations.ReadAllConversations(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/conversations/{id}", operations.UpdateConversation(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/conversations/{id}/members", operations.AddConversationMembers(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/conversations/{id}/members/{member_id}", operations.RemoveConversationMember(opts.Sugar, mongoDb, logger)).Methods("DELETE")
+	r.Handle("/conversations/{id}/members/{member_id}/role", operations.SetConversationRole(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/conversations/{id}/read", operations.ReadConversation(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/follow-requests", operations.GetFollowRequests(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/follow-requests/{id}/accept", operations.AcceptFollowRequest(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/follow-requests/{id}/reject", operations.RejectFollowRequest(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/friend-lists", operations.GetFriendLists(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/friend-lists", operations.CreateFriendList(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/friend-lists/{id}", operations.UpdateFriendList(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/friend-lists/{id}", operations.DeleteFriendList(opts.Sugar, mongoDb, logger)).Methods("DELETE")
 	r.Handle("/friend-requests", operations.GetFriendRequests(opts.Sugar, mongoDb, logger)).Methods("GET")
 	r.Handle("/friend-requests", operations.AddFriendRequest(opts.Sugar, mongoDb, logger)).Methods("POST")
 	r.Handle("/friend-requests/{id}/accept", operations.AcceptFriendRequest(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/friend-requests/{id}/cancel", operations.CancelFriendRequest(opts.Sugar, mongoDb, logger)).Methods("POST")
 	r.Handle("/friend-requests/{id}/reject", operations.RejectFriendRequest(opts.Sugar, mongoDb, logger)).Methods("POST")
 	r.Handle("/friends", operations.GetFriends(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/friends/{friend_id}", operations.RemoveFriend(opts.Sugar, mongoDb, logger)).Methods("DELETE")
+	r.Handle("/friends/suggestions", operations.GetFriendSuggestions(opts.Sugar, mongoDb, logger)).Methods("GET")
 	r.Handle("/login", operations.Login(opts.Sugar, mongoDb, logger)).Methods("POST")
 	r.Handle("/me", operations.Me(opts.Sugar, mongoDb, logger)).Methods("GET")
 	r.Handle("/me", operations.UpdateMe(opts.Sugar, mongoDb, logger)).Methods("POST")
 	r.Handle("/notfriends", operations.GetNotFriends(opts.Sugar, mongoDb, logger)).Methods("GET")
 	r.Handle("/posts", operations.GetPosts(opts.Sugar, mongoDb, logger)).Methods("GET")
 	r.Handle("/posts", operations.CreatePost(opts.Sugar, mongoDb, logger)).Methods("POST")
-	r.Handle("/posts/{id}/comment", operations.GetComments(opts.Sugar, mongoDb, logger)).Methods("GET")
-	r.Handle("/posts/{id}/comment", operations.AddComment(opts.Sugar, mongoDb, logger)).Methods("POST")
-	r.Handle("/posts/{id}/like", operations.LikePost(opts.Sugar, mongoDb, logger)).Methods("POST")
-	r.Handle("/posts/{id}/unlike", operations.UnlikePost(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/posts/drafts", operations.GetPostDrafts(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/posts/{id}", operations.UpdatePost(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/posts/{id}/vote", operations.VotePoll(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/search", operations.Search(opts.Sugar, mongoDb, logger)).Methods("GET")
 	r.Handle("/start-verification", operations.StartVerification(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/stories", operations.GetStories(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/stories", operations.CreateStory(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/stories/{id}/view", operations.ViewStory(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/stories/{id}/viewers", operations.GetStoryViewers(opts.Sugar, mongoDb, logger)).Methods("GET")
 	r.Handle("/uploadlink", operations.UploadLink(mongoDb, logger)).Methods("POST")
 	r.Handle("/users", operations.GetUsers(opts.Sugar, mongoDb, logger)).Methods("GET")
 	r.Handle("/users", operations.Register(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/users/{id}", operations.GetUser(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/users/{id}/block", operations.BlockUser(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/users/{id}/feed.{format:atom|rss|json}", operations.GetUserFeed(mongoDb, logger)).Methods("GET")
+	r.Handle("/users/{id}/follow", operations.FollowUser(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/users/{id}/followers", operations.GetFollowers(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/users/{id}/following", operations.GetFollowing(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/users/{id}/mutual-friends", operations.GetMutualFriends(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/users/{id}/unblock", operations.UnblockUser(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/users/{id}/unfollow", operations.UnfollowUser(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/{target:posts|articles}/{id}/comment", operations.GetComments(opts.Sugar, mongoDb, logger)).Methods("GET")
+	r.Handle("/{target:posts|articles}/{id}/comment", operations.AddComment(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/{target:posts|articles}/{id}/like", operations.Like(opts.Sugar, mongoDb, logger)).Methods("POST")
+	r.Handle("/{target:posts|articles}/{id}/unlike", operations.Unlike(opts.Sugar, mongoDb, logger)).Methods("POST")
 
 	uploadHandler, err := operations.Upload(opts.UploadBucket, logger)
 	if err != nil {
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type Affinity struct {
+	Chats        int `json:"chats,omitempty" bson:"chats,omitempty"`
+	Interactions int `json:"interactions,omitempty" bson:"interactions,omitempty"`
+	OtherId      int `json:"other_id" bson:"other_id"`
+	UserId       int `json:"user_id" bson:"user_id"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *Affinity) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) AffinityFromBody() *Affinity {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &Affinity{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid Affinity")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -5,21 +5,38 @@ import (
 	"io/ioutil"
 	"time"
 	// -- imports --
+	"strings"
 	// -- end --
 )
 
 type Article struct {
-	AuthorName  string     `json:"author_name,omitempty" bson:"author_name,omitempty"`
-	Content     string     `json:"content,omitempty" bson:"content,omitempty"`
-	CreatedAt   *time.Time `json:"created_at,omitempty" bson:"created_at,omitempty"`
-	Description string     `json:"description,omitempty" bson:"description,omitempty"`
-	Id          int        `json:"id,omitempty" bson:"_id,omitempty"`
-	Pdf         string     `json:"pdf,omitempty" bson:"pdf,omitempty"`
-	Photo       string     `json:"photo,omitempty" bson:"photo,omitempty"`
-	ProfilePic  string     `json:"profile_pic,omitempty" bson:"profile_pic,omitempty"`
-	Tags        []string   `json:"tags,omitempty" bson:"tags,omitempty"`
-	Title       string     `json:"title,omitempty" bson:"title,omitempty"`
-	UserId      int        `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	AuthorName       string        `json:"author_name,omitempty" bson:"author_name,omitempty"`
+	CommentsCount    int           `json:"comments_count,omitempty" bson:"comments_count,omitempty"`
+	Content          string        `json:"content,omitempty" bson:"content,omitempty"`
+	CompletionsCount int           `json:"completions_count,omitempty" bson:"completions_count,omitempty"`
+	ContentHtml      string        `json:"content_html,omitempty" bson:"content_html,omitempty"`
+	CreatedAt        *time.Time    `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	Description      string        `json:"description,omitempty" bson:"description,omitempty"`
+	Id               int           `json:"id,omitempty" bson:"_id,omitempty"`
+	Liked            bool          `json:"liked,omitempty" bson:"liked,omitempty"`
+	Likes            []int         `json:"likes,omitempty" bson:"likes,omitempty"`
+	LikesCount       int           `json:"likes_count,omitempty" bson:"likes_count,omitempty"`
+	Pdf              string        `json:"pdf,omitempty" bson:"pdf,omitempty"`
+	PdfInfo          *PdfInfo      `json:"pdf_info,omitempty" bson:"pdf_info,omitempty"`
+	Photo            string        `json:"photo,omitempty" bson:"photo,omitempty"`
+	ProfilePic       string        `json:"profile_pic,omitempty" bson:"profile_pic,omitempty"`
+	PublishAt        *time.Time    `json:"publish_at,omitempty" bson:"publish_at,omitempty"`
+	PublishedAt      *time.Time    `json:"published_at,omitempty" bson:"published_at,omitempty"`
+	ReadersCount     int           `json:"readers_count,omitempty" bson:"readers_count,omitempty"`
+	ReadingTime      int           `json:"reading_time,omitempty" bson:"reading_time,omitempty"`
+	Revision         int           `json:"revision,omitempty" bson:"revision,omitempty"`
+	Saved            bool          `json:"saved,omitempty" bson:"saved,omitempty"`
+	Status           PublishStatus `json:"status,omitempty" bson:"status,omitempty"`
+	Tags             []string      `json:"tags,omitempty" bson:"tags,omitempty"`
+	Title            string        `json:"title,omitempty" bson:"title,omitempty"`
+	Toc              []*TocEntry   `json:"toc,omitempty" bson:"toc,omitempty"`
+	UserId           int           `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	ViewsCount       int           `json:"views_count,omitempty" bson:"views_count,omitempty"`
 
 	// -- extensions --
 	// -- end --
@@ -54,4 +71,21 @@ func (v *Validator) ArticleFromBody() *Article {
 }
 
 // -- code --
+
+// NormalizeTags turns a comma separated list into tags that are trimmed,
+// lowercased and unique, keeping their order.
+func NormalizeTags(raw string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, t := range strings.Split(raw, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	return tags
+}
+
 // -- end --
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type ArticleDayStats struct {
+	Completions int    `json:"completions" bson:"completions"`
+	Day         string `json:"day" bson:"day"`
+	Readers     int    `json:"readers" bson:"readers"`
+	Views       int    `json:"views" bson:"views"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ArticleDayStats) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ArticleDayStatsFromBody() *ArticleDayStats {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ArticleDayStats{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ArticleDayStats")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type ArticleDiff struct {
+	Fields []*FieldDiff `json:"fields" bson:"fields"`
+	From   int          `json:"from" bson:"from"`
+	To     int          `json:"to" bson:"to"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ArticleDiff) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ArticleDiffFromBody() *ArticleDiff {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ArticleDiff{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ArticleDiff")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type ArticleReader struct {
+	ArticleId int        `json:"article_id" bson:"article_id"`
+	Completed bool       `json:"completed,omitempty" bson:"completed,omitempty"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+	Id        int        `json:"id" bson:"_id"`
+	Progress  int        `json:"progress,omitempty" bson:"progress,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	UserId    int        `json:"user_id" bson:"user_id"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ArticleReader) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ArticleReaderFromBody() *ArticleReader {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ArticleReader{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ArticleReader")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type ArticleRevision struct {
+	ArticleId    int       `json:"article_id" bson:"article_id"`
+	AuthorName   string    `json:"author_name,omitempty" bson:"author_name,omitempty"`
+	Content      string    `json:"content,omitempty" bson:"content,omitempty"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+	Description  string    `json:"description,omitempty" bson:"description,omitempty"`
+	Id           int       `json:"id" bson:"_id"`
+	Pdf          string    `json:"pdf,omitempty" bson:"pdf,omitempty"`
+	Photo        string    `json:"photo,omitempty" bson:"photo,omitempty"`
+	RestoredFrom int       `json:"restored_from,omitempty" bson:"restored_from,omitempty"`
+	Revision     int       `json:"revision" bson:"revision"`
+	Tags         []string  `json:"tags,omitempty" bson:"tags,omitempty"`
+	Title        string    `json:"title,omitempty" bson:"title,omitempty"`
+	UserId       int       `json:"user_id" bson:"user_id"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ArticleRevision) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ArticleRevisionFromBody() *ArticleRevision {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ArticleRevision{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ArticleRevision")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
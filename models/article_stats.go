@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type ArticleStats struct {
+	ArticleId        int                `json:"article_id" bson:"article_id"`
+	AverageProgress  float64            `json:"average_progress" bson:"average_progress"`
+	CompletionRate   float64            `json:"completion_rate" bson:"completion_rate"`
+	CompletionsCount int                `json:"completions_count" bson:"completions_count"`
+	Days             []*ArticleDayStats `json:"days" bson:"days"`
+	ReadersCount     int                `json:"readers_count" bson:"readers_count"`
+	ViewsCount       int                `json:"views_count" bson:"views_count"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ArticleStats) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ArticleStatsFromBody() *ArticleStats {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ArticleStats{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ArticleStats")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type ArticleStatsResponse struct {
+	Code   int           `json:"code" bson:"code"`
+	Error  string        `json:"error,omitempty" bson:"error,omitempty"`
+	Result *ArticleStats `json:"result,omitempty" bson:"result,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ArticleStatsResponse) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ArticleStatsResponseFromBody() *ArticleStatsResponse {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ArticleStatsResponse{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ArticleStatsResponse")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type ArticleView struct {
+	ArticleId int       `json:"article_id" bson:"article_id"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	Id        int       `json:"id" bson:"_id"`
+	UserId    int       `json:"user_id" bson:"user_id"`
+	Window    time.Time `json:"window" bson:"window"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ArticleView) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ArticleViewFromBody() *ArticleView {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ArticleView{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ArticleView")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
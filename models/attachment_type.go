@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	// -- imports --
+	// -- end --
+)
+
+type AttachmentType int
+
+const (
+	AttachmentTypeImage AttachmentType = iota
+
+	AttachmentTypeFile
+
+	AttachmentTypeVoice
+)
+
+func (a AttachmentType) String() string {
+	return [...]string{"AttachmentTypeImage", "AttachmentTypeFile", "AttachmentTypeVoice"}[a]
+}
+
+func AttachmentTypeValues() []AttachmentType {
+	return []AttachmentType{AttachmentTypeImage, AttachmentTypeFile, AttachmentTypeVoice}
+}
+
+func AttachmentTypeFromString(s string) (AttachmentType, error) {
+	switch s {
+
+	case "AttachmentTypeImage":
+		return AttachmentTypeImage, nil
+
+	case "AttachmentTypeFile":
+		return AttachmentTypeFile, nil
+
+	case "AttachmentTypeVoice":
+		return AttachmentTypeVoice, nil
+
+	}
+
+	return AttachmentTypeImage, errors.New("Can't parse enum")
+}
+
+func AttachmentTypeFromInt(i int) (AttachmentType, error) {
+	switch AttachmentType(i) {
+
+	case 0:
+		return AttachmentTypeImage, nil
+
+	case 1:
+		return AttachmentTypeFile, nil
+
+	case 2:
+		return AttachmentTypeVoice, nil
+
+	}
+
+	return AttachmentTypeImage, errors.New("Can't parse enum")
+}
+
+// -- code --
+// -- end --
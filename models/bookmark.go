@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type Bookmark struct {
+	Article    *Article   `json:"article,omitempty" bson:"article,omitempty"`
+	Collection string     `json:"collection,omitempty" bson:"collection,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+	Id         int        `json:"id" bson:"_id"`
+	Post       *Post      `json:"post,omitempty" bson:"post,omitempty"`
+	TargetId   int        `json:"target_id" bson:"target_id"`
+	TargetType TargetType `json:"target_type" bson:"target_type"`
+	UserId     int        `json:"user_id" bson:"user_id"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *Bookmark) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) BookmarkFromBody() *Bookmark {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &Bookmark{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid Bookmark")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
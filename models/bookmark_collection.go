@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type BookmarkCollection struct {
+	Count int    `json:"count" bson:"count"`
+	Name  string `json:"name" bson:"name"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *BookmarkCollection) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) BookmarkCollectionFromBody() *BookmarkCollection {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &BookmarkCollection{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid BookmarkCollection")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -9,11 +9,21 @@ import (
 )
 
 type Chat struct {
-	Content   string    `json:"content" bson:"content"`
-	CreatedAt time.Time `json:"created_at" bson:"created_at"`
-	FromId    int       `json:"from_id" bson:"from_id"`
-	Id        int       `json:"id" bson:"_id"`
-	ToId      int       `json:"to_id" bson:"to_id"`
+	Attachments    []*ChatAttachment `json:"attachments,omitempty" bson:"attachments,omitempty"`
+	Content        string            `json:"content" bson:"content"`
+	ConversationId int               `json:"conversation_id,omitempty" bson:"conversation_id,omitempty"`
+	CreatedAt      time.Time         `json:"created_at" bson:"created_at"`
+	DeletedFor     []int             `json:"deleted_for,omitempty" bson:"deleted_for,omitempty"`
+	DeliveredIds   []int             `json:"delivered_ids,omitempty" bson:"delivered_ids,omitempty"`
+	EditedAt       *time.Time        `json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+	FromId         int               `json:"from_id" bson:"from_id"`
+	Id             int               `json:"id" bson:"_id"`
+	Kind           ChatKind          `json:"kind,omitempty" bson:"kind,omitempty"`
+	LinkPreview    *LinkPreview      `json:"link_preview,omitempty" bson:"link_preview,omitempty"`
+	Reactions      []*ChatReaction   `json:"reactions,omitempty" bson:"reactions,omitempty"`
+	TargetId       int               `json:"target_id,omitempty" bson:"target_id,omitempty"`
+	ToId           int               `json:"to_id" bson:"to_id"`
+	Unsent         bool              `json:"unsent,omitempty" bson:"unsent,omitempty"`
 
 	// -- extensions --
 	// -- end --
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type ChatAttachment struct {
+	Duration  float64        `json:"duration,omitempty" bson:"duration,omitempty"`
+	File      string         `json:"file" bson:"file"`
+	Height    int            `json:"height,omitempty" bson:"height,omitempty"`
+	Name      string         `json:"name,omitempty" bson:"name,omitempty"`
+	Size      int            `json:"size,omitempty" bson:"size,omitempty"`
+	Thumbnail string         `json:"thumbnail,omitempty" bson:"thumbnail,omitempty"`
+	Type      AttachmentType `json:"type" bson:"type"`
+	Waveform  []int          `json:"waveform,omitempty" bson:"waveform,omitempty"`
+	Width     int            `json:"width,omitempty" bson:"width,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ChatAttachment) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ChatAttachmentFromBody() *ChatAttachment {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ChatAttachment{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ChatAttachment")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
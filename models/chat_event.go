@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type ChatEvent struct {
+	Attachments    []*ChatAttachment `json:"attachments,omitempty" bson:"attachments,omitempty"`
+	Chat           *Chat             `json:"chat,omitempty" bson:"chat,omitempty"`
+	ChatId         int               `json:"chat_id,omitempty" bson:"chat_id,omitempty"`
+	ClientId       string            `json:"client_id,omitempty" bson:"client_id,omitempty"`
+	Content        string            `json:"content,omitempty" bson:"content,omitempty"`
+	ConversationId int               `json:"conversation_id,omitempty" bson:"conversation_id,omitempty"`
+	Emoji          string            `json:"emoji,omitempty" bson:"emoji,omitempty"`
+	Error          string            `json:"error,omitempty" bson:"error,omitempty"`
+	Kind           ChatEventType     `json:"kind" bson:"kind"`
+	ToId           int               `json:"to_id,omitempty" bson:"to_id,omitempty"`
+	UserId         int               `json:"user_id,omitempty" bson:"user_id,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ChatEvent) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ChatEventFromBody() *ChatEvent {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ChatEvent{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ChatEvent")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+func (u *ChatEvent) Serialize() ([]byte, error) {
+	ret, _ := json.Marshal(u)
+	return ret, nil
+}
+
+func (u *ChatEvent) Parse(b []byte) error {
+	return json.Unmarshal(b, u)
+}
+
+// -- end --
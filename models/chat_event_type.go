@@ -0,0 +1,158 @@
+package models
+
+import (
+	"errors"
+	// -- imports --
+	// -- end --
+)
+
+type ChatEventType int
+
+const (
+	ChatEventTypeSend ChatEventType = iota
+
+	ChatEventTypeMessage
+
+	ChatEventTypeError
+
+	ChatEventTypeSystem
+
+	ChatEventTypeDelivered
+
+	ChatEventTypeRead
+
+	ChatEventTypeTyping
+
+	ChatEventTypeEdit
+
+	ChatEventTypeUnsend
+
+	ChatEventTypeDelete
+
+	ChatEventTypeReact
+
+	ChatEventTypeUpdated
+
+	ChatEventTypeDeleted
+
+	ChatEventTypePreview
+
+	ChatEventTypeThumbnails
+)
+
+func (c ChatEventType) String() string {
+	return [...]string{"ChatEventTypeSend", "ChatEventTypeMessage", "ChatEventTypeError", "ChatEventTypeSystem", "ChatEventTypeDelivered", "ChatEventTypeRead", "ChatEventTypeTyping", "ChatEventTypeEdit", "ChatEventTypeUnsend", "ChatEventTypeDelete", "ChatEventTypeReact", "ChatEventTypeUpdated", "ChatEventTypeDeleted", "ChatEventTypePreview", "ChatEventTypeThumbnails"}[c]
+}
+
+func ChatEventTypeValues() []ChatEventType {
+	return []ChatEventType{ChatEventTypeSend, ChatEventTypeMessage, ChatEventTypeError, ChatEventTypeSystem, ChatEventTypeDelivered, ChatEventTypeRead, ChatEventTypeTyping, ChatEventTypeEdit, ChatEventTypeUnsend, ChatEventTypeDelete, ChatEventTypeReact, ChatEventTypeUpdated, ChatEventTypeDeleted, ChatEventTypePreview, ChatEventTypeThumbnails}
+}
+
+func ChatEventTypeFromString(s string) (ChatEventType, error) {
+	switch s {
+
+	case "ChatEventTypeSend":
+		return ChatEventTypeSend, nil
+
+	case "ChatEventTypeMessage":
+		return ChatEventTypeMessage, nil
+
+	case "ChatEventTypeError":
+		return ChatEventTypeError, nil
+
+	case "ChatEventTypeSystem":
+		return ChatEventTypeSystem, nil
+
+	case "ChatEventTypeDelivered":
+		return ChatEventTypeDelivered, nil
+
+	case "ChatEventTypeRead":
+		return ChatEventTypeRead, nil
+
+	case "ChatEventTypeTyping":
+		return ChatEventTypeTyping, nil
+
+	case "ChatEventTypeEdit":
+		return ChatEventTypeEdit, nil
+
+	case "ChatEventTypeUnsend":
+		return ChatEventTypeUnsend, nil
+
+	case "ChatEventTypeDelete":
+		return ChatEventTypeDelete, nil
+
+	case "ChatEventTypeReact":
+		return ChatEventTypeReact, nil
+
+	case "ChatEventTypeUpdated":
+		return ChatEventTypeUpdated, nil
+
+	case "ChatEventTypeDeleted":
+		return ChatEventTypeDeleted, nil
+
+	case "ChatEventTypePreview":
+		return ChatEventTypePreview, nil
+
+	case "ChatEventTypeThumbnails":
+		return ChatEventTypeThumbnails, nil
+
+	}
+
+	return ChatEventTypeSend, errors.New("Can't parse enum")
+}
+
+func ChatEventTypeFromInt(i int) (ChatEventType, error) {
+	switch ChatEventType(i) {
+
+	case 0:
+		return ChatEventTypeSend, nil
+
+	case 1:
+		return ChatEventTypeMessage, nil
+
+	case 2:
+		return ChatEventTypeError, nil
+
+	case 3:
+		return ChatEventTypeSystem, nil
+
+	case 4:
+		return ChatEventTypeDelivered, nil
+
+	case 5:
+		return ChatEventTypeRead, nil
+
+	case 6:
+		return ChatEventTypeTyping, nil
+
+	case 7:
+		return ChatEventTypeEdit, nil
+
+	case 8:
+		return ChatEventTypeUnsend, nil
+
+	case 9:
+		return ChatEventTypeDelete, nil
+
+	case 10:
+		return ChatEventTypeReact, nil
+
+	case 11:
+		return ChatEventTypeUpdated, nil
+
+	case 12:
+		return ChatEventTypeDeleted, nil
+
+	case 13:
+		return ChatEventTypePreview, nil
+
+	case 14:
+		return ChatEventTypeThumbnails, nil
+
+	}
+
+	return ChatEventTypeSend, errors.New("Can't parse enum")
+}
+
+// -- code --
+// -- end --
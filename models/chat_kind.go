@@ -0,0 +1,70 @@
+package models
+
+import (
+	"errors"
+	// -- imports --
+	// -- end --
+)
+
+type ChatKind int
+
+const (
+	ChatKindText ChatKind = iota
+
+	ChatKindJoined
+
+	ChatKindLeft
+
+	ChatKindUpdated
+)
+
+func (c ChatKind) String() string {
+	return [...]string{"ChatKindText", "ChatKindJoined", "ChatKindLeft", "ChatKindUpdated"}[c]
+}
+
+func ChatKindValues() []ChatKind {
+	return []ChatKind{ChatKindText, ChatKindJoined, ChatKindLeft, ChatKindUpdated}
+}
+
+func ChatKindFromString(s string) (ChatKind, error) {
+	switch s {
+
+	case "ChatKindText":
+		return ChatKindText, nil
+
+	case "ChatKindJoined":
+		return ChatKindJoined, nil
+
+	case "ChatKindLeft":
+		return ChatKindLeft, nil
+
+	case "ChatKindUpdated":
+		return ChatKindUpdated, nil
+
+	}
+
+	return ChatKindText, errors.New("Can't parse enum")
+}
+
+func ChatKindFromInt(i int) (ChatKind, error) {
+	switch ChatKind(i) {
+
+	case 0:
+		return ChatKindText, nil
+
+	case 1:
+		return ChatKindJoined, nil
+
+	case 2:
+		return ChatKindLeft, nil
+
+	case 3:
+		return ChatKindUpdated, nil
+
+	}
+
+	return ChatKindText, errors.New("Can't parse enum")
+}
+
+// -- code --
+// -- end --
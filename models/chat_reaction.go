@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type ChatReaction struct {
+	Emoji  string `json:"emoji" bson:"emoji"`
+	UserId int    `json:"user_id" bson:"user_id"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ChatReaction) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ChatReactionFromBody() *ChatReaction {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ChatReaction{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ChatReaction")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type ChatResponse struct {
+	Code   int    `json:"code" bson:"code"`
+	Error  string `json:"error,omitempty" bson:"error,omitempty"`
+	Result *Chat  `json:"result,omitempty" bson:"result,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ChatResponse) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ChatResponseFromBody() *ChatResponse {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ChatResponse{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ChatResponse")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
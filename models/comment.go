@@ -9,13 +9,17 @@ import (
 )
 
 type Comment struct {
-	Content    string    `json:"content" bson:"content"`
-	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
-	Id         int       `json:"id" bson:"_id"`
-	Name       string    `json:"name,omitempty" bson:"name,omitempty"`
-	PostId     int       `json:"post_id" bson:"post_id"`
-	ProfilePic string    `json:"profile_pic,omitempty" bson:"profile_pic,omitempty"`
-	UserId     int       `json:"user_id" bson:"user_id"`
+	Content      string     `json:"content" bson:"content"`
+	CreatedAt    time.Time  `json:"created_at" bson:"created_at"`
+	Id           int        `json:"id" bson:"_id"`
+	Name         string     `json:"name,omitempty" bson:"name,omitempty"`
+	ParentId     int        `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	PostId       int        `json:"post_id,omitempty" bson:"post_id,omitempty"`
+	ProfilePic   string     `json:"profile_pic,omitempty" bson:"profile_pic,omitempty"`
+	RepliesCount int        `json:"replies_count,omitempty" bson:"replies_count,omitempty"`
+	TargetId     int        `json:"target_id,omitempty" bson:"target_id,omitempty"`
+	TargetType   TargetType `json:"target_type,omitempty" bson:"target_type,omitempty"`
+	UserId       int        `json:"user_id" bson:"user_id"`
 
 	// -- extensions --
 	// -- end --
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type Conversation struct {
+	Avatar         string                `json:"avatar,omitempty" bson:"avatar,omitempty"`
+	CreatedAt      time.Time             `json:"created_at" bson:"created_at"`
+	CreatedBy      int                   `json:"created_by,omitempty" bson:"created_by,omitempty"`
+	Group          bool                  `json:"group,omitempty" bson:"group,omitempty"`
+	Id             int                   `json:"id" bson:"_id"`
+	LastMessage    *Chat                 `json:"last_message,omitempty" bson:"last_message,omitempty"`
+	LastReadId     int                   `json:"last_read_id,omitempty" bson:"last_read_id,omitempty"`
+	Members        []*ConversationMember `json:"members" bson:"members"`
+	Pair           string                `json:"pair,omitempty" bson:"pair,omitempty"`
+	ParticipantIds []int                 `json:"participant_ids" bson:"participant_ids"`
+	Title          string                `json:"title,omitempty" bson:"title,omitempty"`
+	UnreadCount    int                   `json:"unread_count,omitempty" bson:"unread_count,omitempty"`
+	UpdatedAt      time.Time             `json:"updated_at" bson:"updated_at"`
+	Users          []*User               `json:"users,omitempty" bson:"users,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *Conversation) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ConversationFromBody() *Conversation {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &Conversation{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid Conversation")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type ConversationMember struct {
+	JoinedAt    *time.Time       `json:"joined_at,omitempty" bson:"joined_at,omitempty"`
+	LastReadId  int              `json:"last_read_id" bson:"last_read_id"`
+	Role        ConversationRole `json:"role" bson:"role"`
+	UnreadCount int              `json:"unread_count" bson:"unread_count"`
+	UserId      int              `json:"user_id" bson:"user_id"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ConversationMember) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ConversationMemberFromBody() *ConversationMember {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ConversationMember{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ConversationMember")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
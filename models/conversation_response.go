@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type ConversationResponse struct {
+	Code   int           `json:"code" bson:"code"`
+	Error  string        `json:"error,omitempty" bson:"error,omitempty"`
+	Result *Conversation `json:"result,omitempty" bson:"result,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *ConversationResponse) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) ConversationResponseFromBody() *ConversationResponse {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &ConversationResponse{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid ConversationResponse")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	// -- imports --
+	// -- end --
+)
+
+type ConversationRole int
+
+const (
+	ConversationRoleMember ConversationRole = iota
+
+	ConversationRoleAdmin
+)
+
+func (c ConversationRole) String() string {
+	return [...]string{"ConversationRoleMember", "ConversationRoleAdmin"}[c]
+}
+
+func ConversationRoleValues() []ConversationRole {
+	return []ConversationRole{ConversationRoleMember, ConversationRoleAdmin}
+}
+
+func ConversationRoleFromString(s string) (ConversationRole, error) {
+	switch s {
+
+	case "ConversationRoleMember":
+		return ConversationRoleMember, nil
+
+	case "ConversationRoleAdmin":
+		return ConversationRoleAdmin, nil
+
+	}
+
+	return ConversationRoleMember, errors.New("Can't parse enum")
+}
+
+func ConversationRoleFromInt(i int) (ConversationRole, error) {
+	switch ConversationRole(i) {
+
+	case 0:
+		return ConversationRoleMember, nil
+
+	case 1:
+		return ConversationRoleAdmin, nil
+
+	}
+
+	return ConversationRoleMember, errors.New("Can't parse enum")
+}
+
+// -- code --
+// -- end --
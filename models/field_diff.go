@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type FieldDiff struct {
+	Diff  string `json:"diff" bson:"diff"`
+	Field string `json:"field" bson:"field"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *FieldDiff) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) FieldDiffFromBody() *FieldDiff {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &FieldDiff{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid FieldDiff")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
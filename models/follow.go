@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type Follow struct {
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	FromId    int       `json:"from_id" bson:"from_id"`
+	Id        int       `json:"id" bson:"_id"`
+	Pending   bool      `json:"pending,omitempty" bson:"pending,omitempty"`
+	ToId      int       `json:"to_id" bson:"to_id"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *Follow) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) FollowFromBody() *Follow {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &Follow{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid Follow")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"time"
 	// -- imports --
+	"strconv"
 	// -- end --
 )
 
@@ -12,6 +13,7 @@ type FriendEntry struct {
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	FromId    int       `json:"from_id" bson:"from_id"`
 	Id        int       `json:"id" bson:"_id"`
+	Pair      string    `json:"pair,omitempty" bson:"pair,omitempty"`
 	ToId      int       `json:"to_id" bson:"to_id"`
 
 	// -- extensions --
@@ -47,4 +49,14 @@ func (v *Validator) FriendEntryFromBody() *FriendEntry {
 }
 
 // -- code --
+
+// FriendPair identifies the two users of a friendship or friend request
+// regardless of who asked whom, so that only one can exist per pair.
+func FriendPair(a, b int) string {
+	if a > b {
+		a, b = b, a
+	}
+	return strconv.Itoa(a) + ":" + strconv.Itoa(b)
+}
+
 // -- end --
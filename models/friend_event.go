@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type FriendEvent struct {
+	Kind         FriendEventType `json:"friend_event" bson:"friend_event"`
+	Name         string          `json:"name,omitempty" bson:"name,omitempty"`
+	PendingCount int             `json:"pending_count" bson:"pending_count"`
+	ProfilePic   string          `json:"profile_pic,omitempty" bson:"profile_pic,omitempty"`
+	RequestId    int             `json:"request_id,omitempty" bson:"request_id,omitempty"`
+	ToId         int             `json:"to_id,omitempty" bson:"to_id,omitempty"`
+	UserId       int             `json:"user_id,omitempty" bson:"user_id,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *FriendEvent) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) FriendEventFromBody() *FriendEvent {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &FriendEvent{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid FriendEvent")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+func (u *FriendEvent) Serialize() ([]byte, error) {
+	ret, _ := json.Marshal(u)
+	return ret, nil
+}
+
+func (u *FriendEvent) Parse(b []byte) error {
+	return json.Unmarshal(b, u)
+}
+
+// -- end --
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"errors"
+	// -- imports --
+	// -- end --
+)
+
+type FriendEventType int
+
+const (
+	FriendEventTypeRequest FriendEventType = iota
+
+	FriendEventTypeAccepted
+
+	FriendEventTypeRejected
+
+	FriendEventTypeCancelled
+
+	FriendEventTypeRemoved
+
+	FriendEventTypePending
+)
+
+func (f FriendEventType) String() string {
+	return [...]string{"FriendEventTypeRequest", "FriendEventTypeAccepted", "FriendEventTypeRejected", "FriendEventTypeCancelled", "FriendEventTypeRemoved", "FriendEventTypePending"}[f]
+}
+
+func FriendEventTypeValues() []FriendEventType {
+	return []FriendEventType{FriendEventTypeRequest, FriendEventTypeAccepted, FriendEventTypeRejected, FriendEventTypeCancelled, FriendEventTypeRemoved, FriendEventTypePending}
+}
+
+func FriendEventTypeFromString(s string) (FriendEventType, error) {
+	switch s {
+
+	case "FriendEventTypeRequest":
+		return FriendEventTypeRequest, nil
+
+	case "FriendEventTypeAccepted":
+		return FriendEventTypeAccepted, nil
+
+	case "FriendEventTypeRejected":
+		return FriendEventTypeRejected, nil
+
+	case "FriendEventTypeCancelled":
+		return FriendEventTypeCancelled, nil
+
+	case "FriendEventTypeRemoved":
+		return FriendEventTypeRemoved, nil
+
+	case "FriendEventTypePending":
+		return FriendEventTypePending, nil
+
+	}
+
+	return FriendEventTypeRequest, errors.New("Can't parse enum")
+}
+
+func FriendEventTypeFromInt(i int) (FriendEventType, error) {
+	switch FriendEventType(i) {
+
+	case 0:
+		return FriendEventTypeRequest, nil
+
+	case 1:
+		return FriendEventTypeAccepted, nil
+
+	case 2:
+		return FriendEventTypeRejected, nil
+
+	case 3:
+		return FriendEventTypeCancelled, nil
+
+	case 4:
+		return FriendEventTypeRemoved, nil
+
+	case 5:
+		return FriendEventTypePending, nil
+
+	}
+
+	return FriendEventTypeRequest, errors.New("Can't parse enum")
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type FriendList struct {
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	Id        int       `json:"id" bson:"_id"`
+	MemberIds []int     `json:"member_ids,omitempty" bson:"member_ids,omitempty"`
+	Name      string    `json:"name" bson:"name"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	UserId    int       `json:"user_id" bson:"user_id"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *FriendList) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) FriendListFromBody() *FriendList {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &FriendList{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid FriendList")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
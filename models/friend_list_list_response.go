@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type FriendListListResponse struct {
+	Code   int           `json:"code" bson:"code"`
+	Error  string        `json:"error,omitempty" bson:"error,omitempty"`
+	Result []*FriendList `json:"result,omitempty" bson:"result,omitempty"`
+	Start  int           `json:"start" bson:"start"`
+	Total  int           `json:"total" bson:"total"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *FriendListListResponse) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) FriendListListResponseFromBody() *FriendListListResponse {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &FriendListListResponse{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid FriendListListResponse")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
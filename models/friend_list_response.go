@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type FriendListResponse struct {
+	Code   int         `json:"code" bson:"code"`
+	Error  string      `json:"error,omitempty" bson:"error,omitempty"`
+	Result *FriendList `json:"result,omitempty" bson:"result,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *FriendListResponse) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) FriendListResponseFromBody() *FriendListResponse {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &FriendListResponse{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid FriendListResponse")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
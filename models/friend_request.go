@@ -12,6 +12,7 @@ type FriendRequest struct {
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	FromId    int       `json:"from_id" bson:"from_id"`
 	Id        int       `json:"id" bson:"_id"`
+	Pair      string    `json:"pair,omitempty" bson:"pair,omitempty"`
 	ToId      int       `json:"to_id" bson:"to_id"`
 
 	// -- extensions --
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type LinkPreview struct {
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+	Image       string `json:"image,omitempty" bson:"image,omitempty"`
+	SiteName    string `json:"site_name,omitempty" bson:"site_name,omitempty"`
+	Title       string `json:"title,omitempty" bson:"title,omitempty"`
+	Url         string `json:"url" bson:"url"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *LinkPreview) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) LinkPreviewFromBody() *LinkPreview {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &LinkPreview{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid LinkPreview")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
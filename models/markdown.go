@@ -0,0 +1,624 @@
+package models
+
+import (
+	"html"
+	"math"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Article bodies are written in Markdown. Raw HTML in the source is never
+// passed through: all text is escaped, so the only markup in the result is
+// the one produced here, and links and images are limited to safe schemes.
+
+const (
+	// wordsPerMinute is the reading speed used to estimate reading time.
+	wordsPerMinute = 200
+	// MaxMarkdownLength caps the length of an article's content.
+	MaxMarkdownLength = 200000
+)
+
+var (
+	mdUnordered = regexp.MustCompile(`^ {0,3}[-*+][ \t]+(.*)$`)
+	mdOrdered   = regexp.MustCompile(`^ {0,3}(\d{1,9})[.)][ \t]+(.*)$`)
+	mdHeading   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	mdLang      = regexp.MustCompile(`^[A-Za-z0-9_+-]+$`)
+	mdTags      = regexp.MustCompile(`<[^>]*>`)
+)
+
+// mdEscapable are the characters a backslash turns into plain text.
+const mdEscapable = "\\`*_{}[]()#+-.!~<>|"
+
+// RenderMarkdown turns Markdown into sanitized HTML, along with a table of
+// contents built from its headings.
+func RenderMarkdown(src string) (string, []*TocEntry) {
+	src = strings.ReplaceAll(src, "\x00", "�")
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	m := &mdRenderer{slugs: make(map[string]int)}
+	m.blocks(strings.Split(src, "\n"))
+	return m.sb.String(), m.toc
+}
+
+// ReadingTime estimates how many minutes it takes to read rendered HTML.
+func ReadingTime(rendered string) int {
+	words := len(strings.Fields(plainText(rendered)))
+	if words == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(words) / wordsPerMinute))
+}
+
+type mdRenderer struct {
+	sb    strings.Builder
+	toc   []*TocEntry
+	slugs map[string]int
+	// links don't nest, and image descriptions are plain text
+	inLink  bool
+	inImage bool
+}
+
+func (m *mdRenderer) blocks(lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case isIndented(line, 4):
+			var code []string
+			for ; i < len(lines) && (isIndented(lines[i], 4) || strings.TrimSpace(lines[i]) == ""); i++ {
+				code = append(code, dedent(lines[i], 4))
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			m.sb.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case isFence(trimmed):
+			fence := trimmed[:3]
+			lang := strings.TrimSpace(trimmed[3:])
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			i++
+			if mdLang.MatchString(lang) {
+				m.sb.WriteString(`<pre><code class="language-` + lang + `">`)
+			} else {
+				m.sb.WriteString("<pre><code>")
+			}
+			m.sb.WriteString(html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case mdHeading.MatchString(line):
+			g := mdHeading.FindStringSubmatch(line)
+			m.heading(len(g[1]), g[2])
+			i++
+
+		case isRule(trimmed):
+			m.sb.WriteString("<hr>\n")
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(q, " "))
+			}
+			m.sb.WriteString("<blockquote>\n")
+			m.blocks(quote)
+			m.sb.WriteString("</blockquote>\n")
+
+		case mdUnordered.MatchString(line) || mdOrdered.MatchString(line):
+			i = m.list(lines, i)
+
+		default:
+			var para []string
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(para) == 0 || !startsBlock(lines[i])); i++ {
+				para = append(para, lines[i])
+			}
+			m.sb.WriteString("<p>" + m.lines(para) + "</p>\n")
+		}
+	}
+}
+
+// list renders the list starting at lines[i] and returns the index of the
+// first line after it.
+func (m *mdRenderer) list(lines []string, i int) int {
+	ordered := !mdUnordered.MatchString(lines[i])
+	item := mdUnordered
+	tag := "ul"
+	if ordered {
+		item = mdOrdered
+		tag = "ol"
+	}
+
+	if g := mdOrdered.FindStringSubmatch(lines[i]); ordered && g[1] != "1" {
+		n, _ := strconv.Atoi(g[1])
+		m.sb.WriteString(`<ol start="` + strconv.Itoa(n) + `">` + "\n")
+	} else {
+		m.sb.WriteString("<" + tag + ">\n")
+	}
+
+	for i < len(lines) && item.MatchString(lines[i]) {
+		g := item.FindStringSubmatch(lines[i])
+		body := []string{g[len(g)-1]}
+		for i++; i < len(lines); i++ {
+			l := lines[i]
+			if strings.TrimSpace(l) == "" {
+				// a blank line only continues the item if more of it follows
+				j := i + 1
+				for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+					j++
+				}
+				if j < len(lines) && isIndented(lines[j], 2) {
+					body = append(body, "")
+					continue
+				}
+				if j < len(lines) && item.MatchString(lines[j]) {
+					i = j
+				}
+				break
+			}
+			if isIndented(l, 2) {
+				body = append(body, dedent(l, 2))
+				continue
+			}
+			if startsBlock(l) {
+				break
+			}
+			body = append(body, l)
+		}
+
+		// the item's own text, followed by any nested blocks
+		text := 1
+		for text < len(body) && strings.TrimSpace(body[text]) != "" && !startsBlock(body[text]) {
+			text++
+		}
+		m.sb.WriteString("<li>" + m.lines(body[:text]))
+		if text < len(body) {
+			m.sb.WriteString("\n")
+			m.blocks(body[text:])
+		}
+		m.sb.WriteString("</li>\n")
+	}
+
+	m.sb.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func (m *mdRenderer) heading(level int, text string) {
+	content := m.inline(text)
+	title := plainText(content)
+
+	slug := slugify(title)
+	if slug == "" {
+		slug = "section"
+	}
+	if n := m.slugs[slug]; n > 0 {
+		m.slugs[slug] = n + 1
+		slug += "-" + strconv.Itoa(n)
+	} else {
+		m.slugs[slug] = 1
+	}
+
+	m.toc = append(m.toc, &TocEntry{
+		Level:  level,
+		Title:  title,
+		Anchor: slug,
+	})
+
+	h := "h" + strconv.Itoa(level)
+	m.sb.WriteString("<" + h + ` id="` + slug + `">` + content + "</" + h + ">\n")
+}
+
+// lines renders the lines of a paragraph. Lines ending in two spaces or a
+// backslash are kept apart by a line break.
+func (m *mdRenderer) lines(lines []string) string {
+	parts := make([]string, len(lines))
+	for i, l := range lines {
+		l = strings.TrimLeft(l, " \t")
+		if i < len(lines)-1 && (strings.HasSuffix(l, "  ") || strings.HasSuffix(l, "\\")) {
+			l = strings.TrimRight(strings.TrimSuffix(l, "\\"), " ") + "\x00"
+		} else {
+			l = strings.TrimRight(l, " \t")
+		}
+		parts[i] = l
+	}
+	return strings.ReplaceAll(m.inline(strings.Join(parts, "\n")), "\x00", "<br>")
+}
+
+func (m *mdRenderer) inline(s string) string {
+	var sb strings.Builder
+	pairs := mdScan(s)
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch c {
+		case '\\':
+			if i+1 < len(s) && strings.IndexByte(mdEscapable, s[i+1]) >= 0 {
+				sb.WriteString(html.EscapeString(s[i+1 : i+2]))
+				i += 2
+				continue
+			}
+
+		case '`':
+			n := 1
+			for i+n < len(s) && s[i+n] == '`' {
+				n++
+			}
+			if end := pairs.closingTicks(i+n, n); end >= 0 {
+				code := s[i+n : end]
+				sb.WriteString("<code>" + html.EscapeString(strings.TrimSpace(code)) + "</code>")
+				i = end + n
+				continue
+			}
+			sb.WriteString(s[i : i+n])
+			i += n
+			continue
+
+		case '!':
+			if i+1 < len(s) && s[i+1] == '[' && !m.inImage {
+				if alt, dest, n := mdLink(s, i+1, pairs); n > 0 {
+					m.inImage = true
+					alt = plainText(m.inline(alt))
+					m.inImage = false
+					if src, ok := safeURL(dest, true); ok {
+						sb.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(alt) + `">`)
+					} else {
+						sb.WriteString(html.EscapeString(alt))
+					}
+					i += 1 + n
+					continue
+				}
+			}
+
+		case '[':
+			if m.inLink || m.inImage {
+				break
+			}
+			if text, dest, n := mdLink(s, i, pairs); n > 0 {
+				m.inLink = true
+				content := m.inline(text)
+				m.inLink = false
+				if href, ok := safeURL(dest, false); ok {
+					sb.WriteString(`<a href="` + html.EscapeString(href) + `"`)
+					if u, _ := url.Parse(href); u != nil && u.Host != "" {
+						sb.WriteString(` rel="nofollow noopener noreferrer"`)
+					}
+					sb.WriteString(">" + content + "</a>")
+				} else {
+					sb.WriteString(content)
+				}
+				i += n
+				continue
+			}
+
+		case '<':
+			if end := pairs.closingAngle(s, i) - i; end > 0 {
+				dest := s[i+1 : i+end]
+				if !strings.ContainsAny(dest, " \t\n") && strings.Contains(dest, ":") {
+					if href, ok := safeURL(dest, false); ok {
+						sb.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer">` + html.EscapeString(dest) + "</a>")
+						i += end + 1
+						continue
+					}
+				}
+			}
+
+		case '*', '_', '~':
+			if n, out := m.emphasis(s, i, pairs); n > 0 {
+				sb.WriteString(out)
+				i += n
+				continue
+			}
+		}
+
+		sb.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return sb.String()
+}
+
+// emphasis renders the emphasis starting at s[i], returning how much of s
+// it used, or 0 if there is none.
+func (m *mdRenderer) emphasis(s string, i int, pairs *mdPairs) (int, string) {
+	c := s[i]
+	// underscores inside words are just underscores
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return 0, ""
+	}
+
+	double := i+1 < len(s) && s[i+1] == c
+	if c == '~' && !double {
+		return 0, ""
+	}
+
+	d, tag := string(c), "em"
+	if double {
+		d, tag = s[i:i+2], "strong"
+		if c == '~' {
+			tag = "del"
+		}
+	}
+
+	rest := s[i+len(d):]
+	if rest == "" || rest[0] == ' ' || rest[0] == '\n' {
+		return 0, ""
+	}
+	end := strings.Index(rest, d)
+	// a single delimiter must not close on the start of a double one
+	if !double && end >= 0 {
+		if end = pairs.closingSingle(s, c, i+1); end >= 0 {
+			end -= i + 1
+		}
+	}
+	if end <= 0 || rest[end-1] == ' ' {
+		return 0, ""
+	}
+	// a longer run closes the emphasis opened inside this one first, as in
+	// "**bold *and italic***"
+	if double {
+		run := end
+		for run < len(rest) && rest[run] == c {
+			run++
+		}
+		if run-end > len(d) && strings.Count(rest[:end], d[:1])%2 == 1 {
+			end = run - len(d)
+		}
+	}
+	if c == '_' && end+len(d) < len(rest) && isWordByte(rest[end+len(d)]) {
+		return 0, ""
+	}
+
+	return len(d) + end + len(d), "<" + tag + ">" + m.inline(rest[:end]) + "</" + tag + ">"
+}
+
+// mdLink parses "[text](dest)" at s[i], returning how much of s it spans,
+// or 0 if it isn't a link.
+func mdLink(s string, i int, pairs *mdPairs) (string, string, int) {
+	close := pairs.match[i]
+	if close < 0 || close+1 >= len(s) || s[close+1] != '(' {
+		return "", "", 0
+	}
+	end := pairs.match[close+1]
+	if end < 0 {
+		return "", "", 0
+	}
+
+	dest := strings.TrimSpace(s[close+2 : end])
+	// drop an optional title
+	if k := strings.IndexAny(dest, " \t"); k >= 0 {
+		dest = dest[:k]
+	}
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+	return s[i+1 : close], dest, end + 1 - i
+}
+
+// mdPairs holds where the brackets, parentheses and code spans of a piece
+// of inline text close, found in a single pass so that unclosed ones don't
+// each scan the rest of the text.
+type mdPairs struct {
+	// match holds the closing position of each "[" and "(", or -1
+	match []int
+	// ticks holds the start of the backtick runs of each length, and
+	// tickNext how far they were looked through
+	ticks    map[int][]int
+	tickNext map[int]int
+	// single holds, for each position, where the first single "*" or "_"
+	// from there is, skipping doubled ones, -1 if there is none, or -2
+	// before it was looked for
+	single map[byte][]int
+	// angle is the position of the next ">", -1 if there is none, or -2
+	// before it was looked for
+	angle int
+}
+
+func mdScan(s string) *mdPairs {
+	p := &mdPairs{single: make(map[byte][]int), angle: -2}
+	if !strings.ContainsAny(s, "[`") {
+		return p
+	}
+
+	p.match = make([]int, len(s))
+	p.ticks = make(map[int][]int)
+	p.tickNext = make(map[int]int)
+	var brackets, parens []int
+	for i := 0; i < len(s); i++ {
+		p.match[i] = -1
+		switch s[i] {
+		case '\\':
+			// the escaped character can't open or close anything
+			if i+1 < len(s) {
+				i++
+				p.match[i] = -1
+			}
+		case '[':
+			brackets = append(brackets, i)
+		case ']':
+			if n := len(brackets); n > 0 {
+				p.match[brackets[n-1]] = i
+				brackets = brackets[:n-1]
+			}
+		case '(':
+			parens = append(parens, i)
+		case ')':
+			if n := len(parens); n > 0 {
+				p.match[parens[n-1]] = i
+				parens = parens[:n-1]
+			}
+		case '`':
+			n := 1
+			for i+n < len(s) && s[i+n] == '`' {
+				n++
+			}
+			p.ticks[n] = append(p.ticks[n], i)
+			for j := 1; j < n; j++ {
+				p.match[i+j] = -1
+			}
+			i += n - 1
+		}
+	}
+	return p
+}
+
+// closingTicks returns where the first run of n backticks at or after i
+// starts, or -1.
+func (p *mdPairs) closingTicks(i, n int) int {
+	runs := p.ticks[n]
+	k := p.tickNext[n]
+	for k < len(runs) && runs[k] < i {
+		k++
+	}
+	p.tickNext[n] = k
+	if k < len(runs) {
+		return runs[k]
+	}
+	return -1
+}
+
+// closingSingle returns the position of the first "*" or "_" at or after i
+// that isn't followed by another, skipping doubled ones, or -1.
+func (p *mdPairs) closingSingle(s string, c byte, i int) int {
+	memo := p.single[c]
+	if memo == nil {
+		memo = make([]int, len(s)+1)
+		for k := range memo {
+			memo[k] = -2
+		}
+		p.single[c] = memo
+	}
+
+	var seen []int
+	end := -1
+	for i < len(s) {
+		if memo[i] != -2 {
+			end = memo[i]
+			break
+		}
+		seen = append(seen, i)
+		f := strings.IndexByte(s[i:], c)
+		if f < 0 {
+			break
+		}
+		f += i
+		if f+1 < len(s) && s[f+1] == c {
+			i = f + 2
+			continue
+		}
+		end = f
+		break
+	}
+	for _, k := range seen {
+		memo[k] = end
+	}
+	return end
+}
+
+// closingAngle returns the position of the first ">" at or after i, or -1.
+func (p *mdPairs) closingAngle(s string, i int) int {
+	if p.angle == -1 || p.angle >= i {
+		return p.angle
+	}
+	p.angle = strings.IndexByte(s[i:], '>')
+	if p.angle >= 0 {
+		p.angle += i
+	}
+	return p.angle
+}
+
+// safeURL checks a link or image destination. Only web and mail links are
+// allowed, and bare image names refer to uploaded assets.
+func safeURL(dest string, image bool) (string, bool) {
+	if dest == "" {
+		return "", false
+	}
+	u, err := url.Parse(dest)
+	if err != nil {
+		return "", false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.String(), true
+	case "mailto":
+		return u.String(), !image
+	case "":
+	default:
+		return "", false
+	}
+
+	if image && u.Host == "" && !strings.HasPrefix(u.Path, "/") {
+		name := path.Base(u.Path)
+		if name == "." || name == ".." || name == "/" {
+			return "", false
+		}
+		return "/assets/" + url.PathEscape(name), true
+	}
+	return u.String(), true
+}
+
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return isFence(trimmed) || isRule(trimmed) || strings.HasPrefix(trimmed, ">") ||
+		mdHeading.MatchString(line) || mdUnordered.MatchString(line) || mdOrdered.MatchString(line)
+}
+
+func isFence(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+func isRule(trimmed string) bool {
+	s := strings.ReplaceAll(trimmed, " ", "")
+	if len(s) < 3 {
+		return false
+	}
+	return strings.Count(s, s[:1]) == len(s) && strings.Contains("-*_", s[:1])
+}
+
+func isIndented(line string, n int) bool {
+	return strings.HasPrefix(line, "\t") || strings.HasPrefix(line, strings.Repeat(" ", n))
+}
+
+func dedent(line string, n int) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	for i := 0; i < n && strings.HasPrefix(line, " "); i++ {
+		line = line[1:]
+	}
+	return line
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= 0x80 || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
+
+// plainText strips the markup from rendered HTML.
+func plainText(s string) string {
+	return html.UnescapeString(mdTags.ReplaceAllString(s, ""))
+}
+
+// slugify turns a heading into an anchor name.
+func slugify(s string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			dash = false
+			sb.WriteRune(r)
+		case r == ' ' || r == '-' || r == '_':
+			dash = true
+		}
+	}
+	return sb.String()
+}
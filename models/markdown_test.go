@@ -0,0 +1,173 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"paragraph", "Hello\nworld", "<p>Hello\nworld</p>\n"},
+		{"line break", "Hello  \nworld", "<p>Hello<br>\nworld</p>\n"},
+		{"heading", "## Getting started ##", `<h2 id="getting-started">Getting started</h2>` + "\n"},
+		{"emphasis", "*a* **b** _c_ __d__ ~~e~~", "<p><em>a</em> <strong>b</strong> <em>c</em> <strong>d</strong> <del>e</del></p>\n"},
+		{"nested emphasis", "**bold *and italic***", "<p><strong>bold <em>and italic</em></strong></p>\n"},
+		{"strong and em", "***both*** and **a***", "<p><strong><em>both</em></strong> and <strong>a</strong>*</p>\n"},
+		{"emphasis in emphasis", "*an **important** word*", "<p><em>an <strong>important</strong> word</em></p>\n"},
+		{"snake case", "snake_case_name", "<p>snake_case_name</p>\n"},
+		{"unclosed emphasis", "2 * 3 * 4", "<p>2 * 3 * 4</p>\n"},
+		{"code span", "`<b>` and `a*b*c`", "<p><code>&lt;b&gt;</code> and <code>a*b*c</code></p>\n"},
+		{"code span ticks", "``a`b`` and `c``d`", "<p><code>a`b</code> and <code>c``d</code></p>\n"},
+		{"escapes", `\*not em\* \[not link\]`, "<p>*not em* [not link]</p>\n"},
+		{"fenced code", "```go\nif a < b {}\n```", `<pre><code class="language-go">if a &lt; b {}</code></pre>` + "\n"},
+		{"fence language", "```\"><script>\nx\n```", "<pre><code>x</code></pre>\n"},
+		{"indented code", "    <b>x</b>", "<pre><code>&lt;b&gt;x&lt;/b&gt;</code></pre>\n"},
+		{"quote", "> quoted\n> text", "<blockquote>\n<p>quoted\ntext</p>\n</blockquote>\n"},
+		{"list", "- a\n- b", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n"},
+		{"ordered list", "3. a\n4. b", "<ol start=\"3\">\n<li>a</li>\n<li>b</li>\n</ol>\n"},
+		{"nested list", "- a\n  - b", "<ul>\n<li>a\n<ul>\n<li>b</li>\n</ul>\n</li>\n</ul>\n"},
+		{"rule", "---", "<hr>\n"},
+
+		{"link", "[site](https://example.com)", `<p><a href="https://example.com" rel="nofollow noopener noreferrer">site</a></p>` + "\n"},
+		{"relative link", "[home](/articles)", `<p><a href="/articles">home</a></p>` + "\n"},
+		{"link title", `[site](https://example.com "Title")`, `<p><a href="https://example.com" rel="nofollow noopener noreferrer">site</a></p>` + "\n"},
+		{"mailto link", "[mail](mailto:a@example.com)", `<p><a href="mailto:a@example.com">mail</a></p>` + "\n"},
+		{"emphasis in link", "[**bold** link](/x)", `<p><a href="/x"><strong>bold</strong> link</a></p>` + "\n"},
+		{"link in emphasis", "*see [this](/x)*", `<p><em>see <a href="/x">this</a></em></p>` + "\n"},
+		{"brackets in link", "[a [b] c](/x)", `<p><a href="/x">a [b] c</a></p>` + "\n"},
+		{"link in link", "[[a](/x)](/y)", `<p><a href="/y">[a](/x)</a></p>` + "\n"},
+		{"image in link", "[![a](b.png)](/x)", `<p><a href="/x"><img src="/assets/b.png" alt="a"></a></p>` + "\n"},
+		{"link in image", "![a [b](/c)](d.png)", `<p><img src="/assets/d.png" alt="a [b](/c)"></p>` + "\n"},
+		{"parens in link", "[wiki](https://en.wikipedia.org/wiki/Go_(game))", `<p><a href="https://en.wikipedia.org/wiki/Go_(game)" rel="nofollow noopener noreferrer">wiki</a></p>` + "\n"},
+		{"autolink", "<https://example.com>", `<p><a href="https://example.com" rel="nofollow noopener noreferrer">https://example.com</a></p>` + "\n"},
+		{"image", "![a cat](cat.png)", `<p><img src="/assets/cat.png" alt="a cat"></p>` + "\n"},
+		{"remote image", "![a cat](https://example.com/cat.png)", `<p><img src="https://example.com/cat.png" alt="a cat"></p>` + "\n"},
+		{"image path", "![x](../../etc/passwd)", `<p><img src="/assets/passwd" alt="x"></p>` + "\n"},
+
+		{"javascript link", "[click](javascript:alert(1))", "<p>click</p>\n"},
+		{"javascript link case", "[click](JaVaScRiPt:alert(1))", "<p>click</p>\n"},
+		{"javascript link spaced", "[click]( javascript:alert(1) )", "<p>click</p>\n"},
+		{"javascript link bracketed", "[click](<javascript:alert(1)>)", "<p>click</p>\n"},
+		{"vbscript link", "[click](vbscript:msgbox)", "<p>click</p>\n"},
+		{"data link", "[click](data:text/html;base64,PHNjcmlwdD4=)", "<p>click</p>\n"},
+		{"data image", "![x](data:image/svg+xml;base64,PHN2Zz4=)", "<p>x</p>\n"},
+		{"mailto image", "![x](mailto:a@example.com)", "<p>x</p>\n"},
+		{"javascript autolink", "<javascript:alert(1)>", "<p>&lt;javascript:alert(1)&gt;</p>\n"},
+
+		{"raw html", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"raw html attributes", `<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>\n"},
+		{"html block", "<div>\n<iframe src=\"https://example.com\"></iframe>\n</div>", "<p>&lt;div&gt;\n&lt;iframe src=&#34;https://example.com&#34;&gt;&lt;/iframe&gt;\n&lt;/div&gt;</p>\n"},
+		{"html in heading", "# <b>Title</b>", `<h1 id="btitleb">&lt;b&gt;Title&lt;/b&gt;</h1>` + "\n"},
+		{"html in link text", "[<b>x</b>](/x)", `<p><a href="/x">&lt;b&gt;x&lt;/b&gt;</a></p>` + "\n"},
+		{"entities", "&lt;b&gt; &amp;", "<p>&amp;lt;b&amp;gt; &amp;amp;</p>\n"},
+
+		{"quote in href", `[x](https://example.com/"onmouseover="alert(1))`, `<p><a href="https://example.com/%22onmouseover=%22alert%281%29" rel="nofollow noopener noreferrer">x</a></p>` + "\n"},
+		{"quote in relative href", `[x](/a"b'c)`, `<p><a href="/a%22b%27c">x</a></p>` + "\n"},
+		{"quote in alt", `![a" onerror="alert(1)](cat.png)`, `<p><img src="/assets/cat.png" alt="a&#34; onerror=&#34;alert(1)"></p>` + "\n"},
+		{"quote in image name", `![x](a"onerror=alert(1).png)`, `<p><img src="/assets/a%22onerror=alert%281%29.png" alt="x"></p>` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := RenderMarkdown(tt.src)
+			if got != tt.want {
+				t.Errorf("RenderMarkdown(%q)\n got: %q\nwant: %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+var mdQuoted = regexp.MustCompile(`"[^"]*"`)
+
+// TestRenderMarkdownNoMarkup checks that whatever the source, the only
+// tags in the result are the ones the renderer makes, and no attribute
+// carries a script.
+func TestRenderMarkdownNoMarkup(t *testing.T) {
+	allowed := map[string]bool{
+		"p": true, "br": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"em": true, "strong": true, "del": true, "code": true, "pre": true, "blockquote": true,
+		"ul": true, "ol": true, "li": true, "hr": true, "a": true, "img": true,
+	}
+	sources := []string{
+		"<script>alert(1)</script>",
+		"<<script>>alert(1)<</script>>",
+		"[<script>](javascript:alert(1))",
+		"[x](javascript:alert(1) \"<script>\")",
+		"**<b>**</b>",
+		"*[x](/a)*<i>",
+		"![<img src=x onerror=alert(1)>](x.png)",
+		"`</code><script>`",
+		"```\n</code></pre><script>\n```",
+		"> <style>body{}</style>",
+		"- <svg onload=alert(1)>",
+		"<a href=\"javascript:alert(1)\">x</a>",
+		"[x](https://a.com/\"><script>alert(1)</script>)",
+	}
+
+	for _, src := range sources {
+		got, _ := RenderMarkdown(src)
+		for _, tag := range mdTags.FindAllString(got, -1) {
+			name := strings.TrimPrefix(strings.Trim(tag, "<>"), "/")
+			if i := strings.IndexAny(name, " \t\n"); i >= 0 {
+				name = name[:i]
+			}
+			if !allowed[name] {
+				t.Errorf("RenderMarkdown(%q) = %q, has tag %q", src, got, tag)
+			}
+			// attribute values are quoted and escaped, so only what's
+			// outside them can be an attribute
+			lower := strings.ToLower(mdQuoted.ReplaceAllString(tag, `""`))
+			if strings.Contains(lower, "javascript:") || strings.Contains(lower, " on") {
+				t.Errorf("RenderMarkdown(%q) = %q, has unsafe attribute in %q", src, got, tag)
+			}
+		}
+	}
+}
+
+// TestRenderMarkdownLinear checks that unclosed markup doesn't make each
+// delimiter scan the rest of the text.
+func TestRenderMarkdownLinear(t *testing.T) {
+	for _, p := range []string{"[", "![", "[a](", "(", "<", "` ``", "**a ", "*a**", "_a__"} {
+		src := strings.Repeat(p, MaxMarkdownLength/len(p))
+		start := time.Now()
+		RenderMarkdown(src)
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("RenderMarkdown(%q...) took %v", p, d)
+		}
+	}
+}
+
+func TestRenderMarkdownToc(t *testing.T) {
+	_, toc := RenderMarkdown("# Intro\n## Setup\n## Setup\n### *Deep* `dive`\n# !!!")
+	want := []TocEntry{
+		{Level: 1, Title: "Intro", Anchor: "intro"},
+		{Level: 2, Title: "Setup", Anchor: "setup"},
+		{Level: 2, Title: "Setup", Anchor: "setup-1"},
+		{Level: 3, Title: "Deep dive", Anchor: "deep-dive"},
+		{Level: 1, Title: "!!!", Anchor: "section"},
+	}
+	if len(toc) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(toc), len(want))
+	}
+	for i, e := range toc {
+		if *e != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, *e, want[i])
+		}
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	html, _ := RenderMarkdown(strings.Repeat("word ", wordsPerMinute+1))
+	if got := ReadingTime(html); got != 2 {
+		t.Errorf("ReadingTime = %d, want 2", got)
+	}
+	if got := ReadingTime(""); got != 0 {
+		t.Errorf("ReadingTime(\"\") = %d, want 0", got)
+	}
+}
@@ -0,0 +1,440 @@
+package models
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// PDFs attached to articles are read here, without any external tools: the
+// page count comes from the root of the page tree and the text from the
+// content streams. Text drawn with embedded font encodings can't be recovered this
+// way and is left out.
+
+// ErrInvalidPdf is returned when a file is not a PDF.
+var ErrInvalidPdf = errors.New("not a PDF file")
+
+const (
+	// maxPdfStream caps the size of a single decompressed stream.
+	maxPdfStream = 16 << 20
+	// maxPdfInflated caps how much is decompressed from a whole file, so
+	// that many small deflate bombs can't add up.
+	maxPdfInflated = 64 << 20
+	// MaxPdfText caps how much text is kept from a PDF.
+	MaxPdfText = 200000
+)
+
+var (
+	pdfPage        = regexp.MustCompile(`/Type\s*/Page(?:[^A-Za-z]|$)`)
+	pdfRoot        = regexp.MustCompile(`/Root\s+(\d+)\s+\d+\s+R`)
+	pdfPages       = regexp.MustCompile(`/Pages\s+(\d+)\s+\d+\s+R`)
+	pdfCount       = regexp.MustCompile(`/Count\s+(\d+)(?:[^\d.]|$)`)
+	pdfFirst       = regexp.MustCompile(`/First\s+(\d+)`)
+	pdfObjStm      = regexp.MustCompile(`/Type\s*/ObjStm(?:[^A-Za-z]|$)`)
+	pdfStreamStart = regexp.MustCompile(`stream\r?\n`)
+	pdfSpaces      = regexp.MustCompile(`[ \t]+`)
+	pdfBreaks      = regexp.MustCompile(`\s*\n\s*`)
+)
+
+// PdfDocument is what could be read from a PDF file.
+type PdfDocument struct {
+	Pages int
+	Text  string
+}
+
+// IsPdf checks that b starts and ends the way a PDF file does.
+func IsPdf(b []byte) bool {
+	head, tail := b, b
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	if len(tail) > 2048 {
+		tail = tail[len(tail)-2048:]
+	}
+	return bytes.Contains(head, []byte("%PDF-")) && bytes.Contains(tail, []byte("%%EOF"))
+}
+
+// ParsePdf reads the page count and text of a PDF file.
+func ParsePdf(b []byte) (*PdfDocument, error) {
+	if !IsPdf(b) {
+		return nil, ErrInvalidPdf
+	}
+
+	doc := &PdfDocument{}
+	streams := pdfStreams(b)
+
+	doc.Pages = pdfPageCount(b, streams)
+	var text strings.Builder
+	for _, s := range streams {
+		if text.Len() < MaxPdfText && bytes.Contains(s.data, []byte("BT")) && bytes.Contains(s.data, []byte("ET")) {
+			text.WriteString(pdfText(s.data))
+			text.WriteString("\n")
+		}
+	}
+	if doc.Pages == 0 {
+		return nil, ErrInvalidPdf
+	}
+
+	doc.Text = strings.TrimSpace(pdfBreaks.ReplaceAllString(pdfSpaces.ReplaceAllString(text.String(), " "), "\n"))
+	if len(doc.Text) > MaxPdfText {
+		doc.Text = strings.ToValidUTF8(doc.Text[:MaxPdfText], "")
+	}
+	return doc, nil
+}
+
+// pdfStream is the dictionary and decoded data of a stream object.
+type pdfStream struct {
+	dict []byte
+	data []byte
+}
+
+// pdfStreams returns all streams that are either uncompressed or deflated.
+// Once maxPdfInflated bytes were decompressed, the remaining deflated
+// streams are left out.
+func pdfStreams(b []byte) []*pdfStream {
+	var streams []*pdfStream
+	budget := int64(maxPdfInflated)
+	for _, loc := range pdfStreamStart.FindAllIndex(b, -1) {
+		// the keyword follows the stream's dictionary, and is not the end
+		// of the previous stream
+		if bytes.HasSuffix(b[:loc[0]], []byte("end")) || !bytes.Contains(b[maxInt(0, loc[0]-256):loc[0]], []byte(">>")) {
+			continue
+		}
+		start := loc[1]
+		end := bytes.Index(b[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		data := bytes.TrimRight(b[start:start+end], "\r\n")
+
+		dictStart := bytes.LastIndex(b[:loc[0]], []byte("obj"))
+		if dictStart < 0 {
+			continue
+		}
+		dict := b[dictStart:loc[0]]
+
+		if bytes.Contains(dict, []byte("/Filter")) {
+			if !bytes.Contains(dict, []byte("/FlateDecode")) || bytes.Count(dict, []byte("Decode")) > 1 || budget <= 0 {
+				continue
+			}
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				continue
+			}
+			limit := int64(maxPdfStream)
+			if budget < limit {
+				limit = budget
+			}
+			// damaged streams still give what could be read
+			data, _ = io.ReadAll(io.LimitReader(r, limit))
+			r.Close()
+			budget -= int64(len(data))
+		}
+		streams = append(streams, &pdfStream{dict: dict, data: data})
+	}
+	return streams
+}
+
+// pdfPageCount reads the page count from the root of the page tree. Files
+// whose catalog can't be found, as happens with damaged ones, fall back to
+// counting the page objects.
+func pdfPageCount(b []byte, streams []*pdfStream) int {
+	// the last trailer is the one of the latest update
+	if roots := pdfRoot.FindAllSubmatch(b, -1); len(roots) > 0 {
+		catalog := pdfObject(b, streams, string(roots[len(roots)-1][1]))
+		if m := pdfPages.FindSubmatch(catalog); m != nil {
+			if m := pdfCount.FindSubmatch(pdfObject(b, streams, string(m[1]))); m != nil {
+				if n, err := strconv.Atoi(string(m[1])); err == nil {
+					return n
+				}
+			}
+		}
+	}
+
+	// pages may sit in the file itself or in compressed object streams
+	pages := len(pdfPage.FindAllIndex(b, -1))
+	for _, s := range streams {
+		pages += len(pdfPage.FindAllIndex(s.data, -1))
+	}
+	return pages
+}
+
+// pdfObject returns the body of object num, which is either in the file
+// itself or in one of its object streams. It returns nil if the object
+// can't be found.
+func pdfObject(b []byte, streams []*pdfStream, num string) []byte {
+	// later definitions of an object replace earlier ones
+	header := regexp.MustCompile(`(?:^|[^0-9])` + num + `\s+\d+\s+obj(?:[^A-Za-z]|$)`)
+	if locs := header.FindAllIndex(b, -1); len(locs) > 0 {
+		body := b[locs[len(locs)-1][1]:]
+		if end := bytes.Index(body, []byte("endobj")); end >= 0 {
+			body = body[:end]
+		}
+		return body
+	}
+
+	for _, s := range streams {
+		if !pdfObjStm.Match(s.dict) {
+			continue
+		}
+		m := pdfFirst.FindSubmatch(s.dict)
+		if m == nil {
+			continue
+		}
+		first, err := strconv.Atoi(string(m[1]))
+		if err != nil || first > len(s.data) {
+			continue
+		}
+
+		// the stream starts with pairs of object numbers and offsets
+		fields := strings.Fields(string(s.data[:first]))
+		for i := 0; i+1 < len(fields); i += 2 {
+			if fields[i] != num {
+				continue
+			}
+			start, err := strconv.Atoi(fields[i+1])
+			if err != nil || first+start > len(s.data) {
+				break
+			}
+			end := len(s.data)
+			if i+3 < len(fields) {
+				if next, err := strconv.Atoi(fields[i+3]); err == nil && next >= start && first+next <= len(s.data) {
+					end = first + next
+				}
+			}
+			return s.data[first+start : end]
+		}
+	}
+	return nil
+}
+
+// pdfText pulls the strings shown by the text operators of a content
+// stream.
+func pdfText(s []byte) string {
+	var sb strings.Builder
+	var operands []string
+	var array []string
+	inArray := false
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case isPdfSpace(c):
+			i++
+
+		case c == '%':
+			for i < len(s) && s[i] != '\n' && s[i] != '\r' {
+				i++
+			}
+
+		case c == '(':
+			str, n := pdfLiteral(s[i:])
+			i += n
+			if inArray {
+				array = append(array, str)
+			} else {
+				operands = append(operands, str)
+			}
+
+		case c == '<' && i+1 < len(s) && s[i+1] == '<':
+			i += 2
+
+		case c == '>' && i+1 < len(s) && s[i+1] == '>':
+			i += 2
+
+		case c == '<':
+			end := bytes.IndexByte(s[i:], '>')
+			if end < 0 {
+				return sb.String()
+			}
+			str := pdfHex(s[i+1 : i+end])
+			i += end + 1
+			if inArray {
+				array = append(array, str)
+			} else {
+				operands = append(operands, str)
+			}
+
+		case c == '[':
+			inArray = true
+			array = array[:0]
+			i++
+
+		case c == ']':
+			inArray = false
+			i++
+
+		default:
+			j := i + 1
+			for j < len(s) && !isPdfSpace(s[j]) && !strings.ContainsRune("()<>[]{}/%", rune(s[j])) {
+				j++
+			}
+			tok := string(s[i:j])
+			i = j
+
+			// names are never shown
+			if c == '/' {
+				continue
+			}
+			if n, err := strconv.ParseFloat(tok, 64); err == nil {
+				if inArray {
+					// wide negative spacing in TJ separates words
+					if n < -200 {
+						array = append(array, " ")
+					}
+				} else {
+					operands = append(operands, tok)
+				}
+				continue
+			}
+
+			switch tok {
+			case "Tj":
+				sb.WriteString(strings.Join(operands, ""))
+			case "'", "\"":
+				sb.WriteString("\n")
+				sb.WriteString(strings.Join(operands, ""))
+			case "TJ":
+				sb.WriteString(strings.Join(array, ""))
+				array = array[:0]
+			case "T*", "ET", "Tm":
+				sb.WriteString("\n")
+			case "Td", "TD":
+				if len(operands) == 2 && operands[1] != "0" {
+					sb.WriteString("\n")
+				} else {
+					sb.WriteString(" ")
+				}
+			}
+			operands = operands[:0]
+		}
+	}
+	return sb.String()
+}
+
+// pdfLiteral decodes the literal string at the start of s and returns how
+// many bytes it spans.
+func pdfLiteral(s []byte) (string, int) {
+	var out []byte
+	depth := 0
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '(':
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfDecode(out), i + 1
+			}
+		case '\\':
+			i++
+			if i >= len(s) {
+				break
+			}
+			switch e := s[i]; e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b', 'f':
+			case '\r':
+				if i+1 < len(s) && s[i+1] == '\n' {
+					i++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					n := 0
+					k := 0
+					for ; k < 3 && i+k < len(s) && s[i+k] >= '0' && s[i+k] <= '7'; k++ {
+						n = n*8 + int(s[i+k]-'0')
+					}
+					i += k - 1
+					out = append(out, byte(n))
+				} else {
+					out = append(out, e)
+				}
+			}
+			continue
+		}
+		out = append(out, c)
+	}
+	return pdfDecode(out), i
+}
+
+func pdfHex(s []byte) string {
+	var out []byte
+	hi := -1
+	for _, c := range s {
+		var v int
+		switch {
+		case c >= '0' && c <= '9':
+			v = int(c - '0')
+		case c >= 'a' && c <= 'f':
+			v = int(c-'a') + 10
+		case c >= 'A' && c <= 'F':
+			v = int(c-'A') + 10
+		default:
+			continue
+		}
+		if hi < 0 {
+			hi = v
+		} else {
+			out = append(out, byte(hi<<4|v))
+			hi = -1
+		}
+	}
+	if hi >= 0 {
+		out = append(out, byte(hi<<4))
+	}
+	return pdfDecode(out)
+}
+
+// pdfDecode turns string bytes into text. Strings that don't look like
+// text, such as glyph ids of embedded fonts, are dropped.
+func pdfDecode(b []byte) string {
+	var runes []rune
+	if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+		u := make([]uint16, 0, len(b)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		runes = utf16.Decode(u)
+	} else {
+		runes = make([]rune, len(b))
+		for i, c := range b {
+			runes[i] = rune(c)
+		}
+	}
+
+	for _, r := range runes {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return ""
+		}
+	}
+	return string(runes)
+}
+
+func isPdfSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type PdfInfo struct {
+	File      string `json:"file" bson:"file"`
+	Pages     int    `json:"pages,omitempty" bson:"pages,omitempty"`
+	Size      int    `json:"size,omitempty" bson:"size,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty" bson:"thumbnail,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *PdfInfo) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) PdfInfoFromBody() *PdfInfo {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &PdfInfo{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid PdfInfo")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testPdf builds a PDF file out of object bodies, numbered from 1, with
+// object 1 as its catalog.
+func testPdf(objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n")
+	for i, o := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\n%%%%EOF\n", len(objects)+1)
+	return b.Bytes()
+}
+
+func deflated(s []byte) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write(s)
+	w.Close()
+	return b.Bytes()
+}
+
+func flateStream(s []byte) string {
+	d := deflated(s)
+	return fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(d), d)
+}
+
+func plainStream(s string) string {
+	return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(s), s)
+}
+
+func TestParsePdfInvalid(t *testing.T) {
+	for _, b := range [][]byte{nil, []byte("hello"), []byte("%PDF-1.7\nno end")} {
+		if _, err := ParsePdf(b); err != ErrInvalidPdf {
+			t.Errorf("ParsePdf(%q) error = %v, want ErrInvalidPdf", b, err)
+		}
+	}
+}
+
+func TestParsePdfCountFromPageTree(t *testing.T) {
+	// the root node's /Count is what counts, not the /Page objects, of
+	// which intermediate nodes and unused pages would give the wrong total
+	b := testPdf(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 3 >>",
+		"<< /Type /Pages /Parent 2 0 R /Kids [4 0 R 5 0 R 6 0 R] /Count 3 >>",
+		"<< /Type /Page /Parent 3 0 R /Contents 7 0 R >>",
+		"<< /Type /Page /Parent 3 0 R >>",
+		"<< /Type /Page /Parent 3 0 R >>",
+		flateStream([]byte("BT /F1 12 Tf 72 712 Td (Hello) Tj ET")),
+		"<< /Type /Page >>",
+	)
+	doc, err := ParsePdf(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Pages != 3 {
+		t.Errorf("Pages = %d, want 3", doc.Pages)
+	}
+	if doc.Text != "Hello" {
+		t.Errorf("Text = %q, want %q", doc.Text, "Hello")
+	}
+}
+
+func TestParsePdfObjectStream(t *testing.T) {
+	// the catalog and page tree are compressed into object 3
+	catalog := "<< /Type /Catalog /Pages 2 0 R >>"
+	pages := "<< /Type /Pages /Kids [] /Count 12 >>"
+	header := fmt.Sprintf("1 0 2 %d ", len(catalog)+1)
+	objs := header + catalog + " " + pages
+	d := deflated([]byte(objs))
+	stm := fmt.Sprintf("<< /Type /ObjStm /N 2 /First %d /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(header), len(d), d)
+
+	b := testPdf("<< /Type /XRef >>", "<< >>", stm)
+	b = bytes.Replace(b, []byte("1 0 obj\n<< /Type /XRef >>\nendobj\n"), nil, 1)
+	b = bytes.Replace(b, []byte("2 0 obj\n<< >>\nendobj\n"), nil, 1)
+
+	doc, err := ParsePdf(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Pages != 12 {
+		t.Errorf("Pages = %d, want 12", doc.Pages)
+	}
+}
+
+func TestParsePdfLatestUpdate(t *testing.T) {
+	b := testPdf(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [] /Count 1 >>",
+	)
+	// an incremental update replaces the page tree
+	b = append(b, "2 0 obj\n<< /Type /Pages /Kids [] /Count 4 >>\nendobj\ntrailer\n<< /Root 1 0 R /Prev 9 >>\n%%EOF\n"...)
+
+	doc, err := ParsePdf(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Pages != 4 {
+		t.Errorf("Pages = %d, want 4", doc.Pages)
+	}
+}
+
+func TestParsePdfWithoutCatalog(t *testing.T) {
+	b := []byte("%PDF-1.4\n1 0 obj\n<< /Type /Page >>\nendobj\n2 0 obj\n<< /Type /Page >>\nendobj\n%%EOF\n")
+	doc, err := ParsePdf(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Pages != 2 {
+		t.Errorf("Pages = %d, want 2", doc.Pages)
+	}
+}
+
+func TestParsePdfText(t *testing.T) {
+	content := strings.Join([]string{
+		"BT",
+		"/F1 12 Tf 72 712 Td",
+		"(Escaped \\(parens\\) and \\101) Tj",
+		"0 -14 Td",
+		"[(Spaced) -300 (words)] TJ",
+		"T* <FEFF00480069> Tj",
+		"ET",
+	}, "\n")
+	b := testPdf(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		plainStream(content),
+	)
+
+	doc, err := ParsePdf(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Escaped (parens) and A\nSpaced words\nHi"
+	if doc.Text != want {
+		t.Errorf("Text = %q, want %q", doc.Text, want)
+	}
+}
+
+func TestParsePdfInflationBudget(t *testing.T) {
+	// each stream inflates to the largest size allowed, and together they
+	// go well past what a whole file may inflate to
+	bomb := flateStream(make([]byte, maxPdfStream))
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [] /Count 1 >>",
+	}
+	for i := 0; i < 2*maxPdfInflated/maxPdfStream; i++ {
+		objects = append(objects, bomb)
+	}
+	b := testPdf(objects...)
+
+	total := 0
+	for _, s := range pdfStreams(b) {
+		total += len(s.data)
+	}
+	if total > maxPdfInflated {
+		t.Errorf("inflated %d bytes, want at most %d", total, maxPdfInflated)
+	}
+
+	doc, err := ParsePdf(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Pages != 1 {
+		t.Errorf("Pages = %d, want 1", doc.Pages)
+	}
+}
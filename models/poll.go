@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type Poll struct {
+	Closed      bool          `json:"closed,omitempty" bson:"closed,omitempty"`
+	ClosesAt    *time.Time    `json:"closes_at,omitempty" bson:"closes_at,omitempty"`
+	HideResults bool          `json:"hide_results,omitempty" bson:"hide_results,omitempty"`
+	MultiChoice bool          `json:"multi_choice,omitempty" bson:"multi_choice,omitempty"`
+	MyVotes     []int         `json:"my_votes,omitempty" bson:"my_votes,omitempty"`
+	Options     []*PollOption `json:"options" bson:"options"`
+	TotalVotes  int           `json:"total_votes,omitempty" bson:"total_votes,omitempty"`
+	Voted       bool          `json:"voted,omitempty" bson:"voted,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *Poll) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) PollFromBody() *Poll {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &Poll{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid Poll")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type PollOption struct {
+	Id    int    `json:"id" bson:"_id"`
+	Text  string `json:"text" bson:"text"`
+	Votes int    `json:"votes,omitempty" bson:"votes,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *PollOption) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) PollOptionFromBody() *PollOption {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &PollOption{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid PollOption")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type PollVote struct {
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	Id        int       `json:"id" bson:"_id"`
+	OptionIds []int     `json:"option_ids" bson:"option_ids"`
+	PostId    int       `json:"post_id" bson:"post_id"`
+	UserId    int       `json:"user_id" bson:"user_id"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *PollVote) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) PollVoteFromBody() *PollVote {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &PollVote{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid PollVote")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
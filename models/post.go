@@ -5,25 +5,42 @@ import (
 	"io/ioutil"
 	"time"
 	// -- imports --
+	"fmt"
+	"math"
 	// -- end --
 )
 
 type Post struct {
-	CommentsCount int       `json:"comments_count,omitempty" bson:"comments_count,omitempty"`
-	Content       string    `json:"content,omitempty" bson:"content,omitempty"`
-	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
-	Id            int       `json:"id" bson:"_id"`
-	Image         string    `json:"image,omitempty" bson:"image,omitempty"`
-	Liked         bool      `json:"liked,omitempty" bson:"liked,omitempty"`
-	Likes         []int     `json:"likes,omitempty" bson:"likes,omitempty"`
-	LikesCount    int       `json:"likes_count,omitempty" bson:"likes_count,omitempty"`
-	Name          string    `json:"name,omitempty" bson:"name,omitempty"`
-	ProfilePic    string    `json:"profile_pic,omitempty" bson:"profile_pic,omitempty"`
-	Title         string    `json:"title,omitempty" bson:"title,omitempty"`
-	UserId        int       `json:"user_id" bson:"user_id"`
-	Video         string    `json:"video,omitempty" bson:"video,omitempty"`
+	CommentsCount int           `json:"comments_count,omitempty" bson:"comments_count,omitempty"`
+	Content       string        `json:"content,omitempty" bson:"content,omitempty"`
+	CreatedAt     time.Time     `json:"created_at" bson:"created_at"`
+	Id            int           `json:"id" bson:"_id"`
+	Image         string        `json:"image,omitempty" bson:"image,omitempty"`
+	Kind          PostKind      `json:"kind,omitempty" bson:"kind,omitempty"`
+	Liked         bool          `json:"liked,omitempty" bson:"liked,omitempty"`
+	Likes         []int         `json:"likes,omitempty" bson:"likes,omitempty"`
+	LikesCount    int           `json:"likes_count,omitempty" bson:"likes_count,omitempty"`
+	ListId        int           `json:"list_id,omitempty" bson:"list_id,omitempty"`
+	Name          string        `json:"name,omitempty" bson:"name,omitempty"`
+	Poll          *Poll         `json:"poll,omitempty" bson:"poll,omitempty"`
+	ProfilePic    string        `json:"profile_pic,omitempty" bson:"profile_pic,omitempty"`
+	PublishAt     *time.Time    `json:"publish_at,omitempty" bson:"publish_at,omitempty"`
+	Rank          float64       `json:"rank,omitempty" bson:"rank,omitempty"`
+	Saved         bool          `json:"saved,omitempty" bson:"saved,omitempty"`
+	Score         float64       `json:"score,omitempty" bson:"score,omitempty"`
+	Status        PublishStatus `json:"status,omitempty" bson:"status,omitempty"`
+	Title         string        `json:"title,omitempty" bson:"title,omitempty"`
+	UserId        int           `json:"user_id" bson:"user_id"`
+	Video         string        `json:"video,omitempty" bson:"video,omitempty"`
+	Visibility    Visibility    `json:"visibility,omitempty" bson:"visibility,omitempty"`
 
 	// -- extensions --
+	// RankVersion, RankedAt, RankedEngagement and Velocity are kept up by
+	// Rerank and never sent to clients.
+	RankVersion      string     `json:"-" bson:"rank_version,omitempty"`
+	RankedAt         *time.Time `json:"-" bson:"ranked_at,omitempty"`
+	RankedEngagement float64    `json:"-" bson:"ranked_engagement,omitempty"`
+	Velocity         float64    `json:"-" bson:"velocity,omitempty"`
 	// -- end --
 }
 
@@ -56,4 +73,110 @@ func (v *Validator) PostFromBody() *Post {
 }
 
 // -- code --
+
+// FeedWeights tune how the ranked feed orders posts.
+type FeedWeights struct {
+	// RecencyHalfLife is the age at which a post counts half as much as a
+	// brand new one with the same engagement.
+	RecencyHalfLife time.Duration
+	// Engagement scales the weight of likes and comments.
+	Engagement float64
+	// Comment is how many likes a single comment is worth.
+	Comment float64
+	// Velocity scales the weight of how much engagement a post gained per
+	// hour lately.
+	Velocity float64
+	// RankWindow is how old posts may be and still have their rank follow
+	// their engagement as time passes.
+	RankWindow time.Duration
+	// Media is the bonus for posts carrying a video, half of it for images.
+	Media float64
+	// Affinity scales how much the viewer's chats and interactions with the
+	// author lift a post.
+	Affinity float64
+	// Candidates is how many of the highest ranked posts get scored per
+	// request.
+	Candidates int
+}
+
+var feedWeights = FeedWeights{
+	RecencyHalfLife: 24 * time.Hour,
+	Engagement:      1,
+	Comment:         2,
+	Velocity:        0.5,
+	RankWindow:      72 * time.Hour,
+	Media:           0.3,
+	Affinity:        1,
+	Candidates:      300,
+}
+
+var rankVersion = weightsVersion(feedWeights)
+
+// SetFeedWeights replaces the weights used for ranking. It is meant to be
+// called once at startup.
+func SetFeedWeights(w FeedWeights) {
+	feedWeights = w
+	rankVersion = weightsVersion(w)
+}
+
+// GetFeedWeights returns the weights used for ranking.
+func GetFeedWeights() FeedWeights {
+	return feedWeights
+}
+
+// RankVersion identifies the weights that go into stored ranks. Posts
+// ranked with other weights need ranking again.
+func RankVersion() string {
+	return rankVersion
+}
+
+func weightsVersion(w FeedWeights) string {
+	return fmt.Sprint(w.RecencyHalfLife, "/", w.Engagement, "/", w.Comment, "/", w.Velocity, "/", w.Media)
+}
+
+// Engagement is how much the post was interacted with, in likes.
+func (t *Post) Engagement() float64 {
+	return float64(len(t.Likes)) + feedWeights.Comment*float64(t.CommentsCount)
+}
+
+// Rerank ranks the post again at ct, along with how fast its engagement
+// grew since it was last ranked. The rate is averaged with the previous
+// one, so a burst of likes fades out over a few runs rather than at once.
+func (t *Post) Rerank(ct time.Time) {
+	e := t.Engagement()
+	if t.RankedAt != nil {
+		if hours := ct.Sub(*t.RankedAt).Hours(); hours > 0 {
+			t.Velocity = (t.Velocity + math.Max(0, e-t.RankedEngagement)/hours) / 2
+		}
+	}
+	t.RankedAt = &ct
+	t.RankedEngagement = e
+	t.RankVersion = rankVersion
+	t.Rank = t.FeedRank()
+}
+
+// FeedRank scores the post for the ranked feed, independently of who is
+// looking at it. Scores are kept in log space, where halving a post's
+// weight every RecencyHalfLife is a constant amount per second of its
+// creation time. Newer posts therefore simply start out higher, and only
+// the velocity part of a rank changes as time passes.
+func (t *Post) FeedRank() float64 {
+	w := feedWeights
+
+	rank := w.Engagement * math.Log10(1+t.Engagement())
+	rank += w.Velocity * math.Log10(1+t.Velocity)
+
+	if t.Video != "" {
+		rank += w.Media
+	} else if t.Image != "" {
+		rank += w.Media / 2
+	}
+
+	if w.RecencyHalfLife > 0 {
+		age := float64(t.CreatedAt.Unix() - Epoch)
+		rank += age / w.RecencyHalfLife.Seconds() * math.Log10(2)
+	}
+	return rank
+}
+
 // -- end --
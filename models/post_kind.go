@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	// -- imports --
+	// -- end --
+)
+
+type PostKind int
+
+const (
+	PostKindText PostKind = iota
+
+	PostKindPoll
+)
+
+func (p PostKind) String() string {
+	return [...]string{"PostKindText", "PostKindPoll"}[p]
+}
+
+func PostKindValues() []PostKind {
+	return []PostKind{PostKindText, PostKindPoll}
+}
+
+func PostKindFromString(s string) (PostKind, error) {
+	switch s {
+
+	case "PostKindText":
+		return PostKindText, nil
+
+	case "PostKindPoll":
+		return PostKindPoll, nil
+
+	}
+
+	return PostKindText, errors.New("Can't parse enum")
+}
+
+func PostKindFromInt(i int) (PostKind, error) {
+	switch PostKind(i) {
+
+	case 0:
+		return PostKindText, nil
+
+	case 1:
+		return PostKindPoll, nil
+
+	}
+
+	return PostKindText, errors.New("Can't parse enum")
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	// -- imports --
+	// -- end --
+)
+
+type PublishStatus int
+
+const (
+	PublishStatusPublished PublishStatus = iota
+
+	PublishStatusDraft
+
+	PublishStatusScheduled
+
+	PublishStatusReview
+
+	PublishStatusArchived
+)
+
+func (p PublishStatus) String() string {
+	return [...]string{"PublishStatusPublished", "PublishStatusDraft", "PublishStatusScheduled", "PublishStatusReview", "PublishStatusArchived"}[p]
+}
+
+func PublishStatusValues() []PublishStatus {
+	return []PublishStatus{PublishStatusPublished, PublishStatusDraft, PublishStatusScheduled, PublishStatusReview, PublishStatusArchived}
+}
+
+func PublishStatusFromString(s string) (PublishStatus, error) {
+	switch s {
+
+	case "PublishStatusPublished":
+		return PublishStatusPublished, nil
+
+	case "PublishStatusDraft":
+		return PublishStatusDraft, nil
+
+	case "PublishStatusScheduled":
+		return PublishStatusScheduled, nil
+
+	case "PublishStatusReview":
+		return PublishStatusReview, nil
+
+	case "PublishStatusArchived":
+		return PublishStatusArchived, nil
+
+	}
+
+	return PublishStatusPublished, errors.New("Can't parse enum")
+}
+
+func PublishStatusFromInt(i int) (PublishStatus, error) {
+	switch PublishStatus(i) {
+
+	case 0:
+		return PublishStatusPublished, nil
+
+	case 1:
+		return PublishStatusDraft, nil
+
+	case 2:
+		return PublishStatusScheduled, nil
+
+	case 3:
+		return PublishStatusReview, nil
+
+	case 4:
+		return PublishStatusArchived, nil
+
+	}
+
+	return PublishStatusPublished, errors.New("Can't parse enum")
+}
+
+// -- code --
+// -- end --
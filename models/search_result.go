@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type SearchResult struct {
+	Article *Article   `json:"article,omitempty" bson:"article,omitempty"`
+	Post    *Post      `json:"post,omitempty" bson:"post,omitempty"`
+	Score   float64    `json:"score" bson:"score"`
+	Type    SearchType `json:"type" bson:"type"`
+	User    *User      `json:"user,omitempty" bson:"user,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *SearchResult) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) SearchResultFromBody() *SearchResult {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &SearchResult{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid SearchResult")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
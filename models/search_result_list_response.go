@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type SearchResultListResponse struct {
+	Code   int             `json:"code" bson:"code"`
+	Error  string          `json:"error,omitempty" bson:"error,omitempty"`
+	Result []*SearchResult `json:"result,omitempty" bson:"result,omitempty"`
+	Start  int             `json:"start" bson:"start"`
+	Total  int             `json:"total" bson:"total"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *SearchResultListResponse) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) SearchResultListResponseFromBody() *SearchResultListResponse {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &SearchResultListResponse{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid SearchResultListResponse")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	// -- imports --
+	// -- end --
+)
+
+type SearchType int
+
+const (
+	SearchTypeUser SearchType = iota
+
+	SearchTypePost
+
+	SearchTypeArticle
+)
+
+func (s SearchType) String() string {
+	return [...]string{"SearchTypeUser", "SearchTypePost", "SearchTypeArticle"}[s]
+}
+
+func SearchTypeValues() []SearchType {
+	return []SearchType{SearchTypeUser, SearchTypePost, SearchTypeArticle}
+}
+
+func SearchTypeFromString(s string) (SearchType, error) {
+	switch s {
+
+	case "SearchTypeUser":
+		return SearchTypeUser, nil
+
+	case "SearchTypePost":
+		return SearchTypePost, nil
+
+	case "SearchTypeArticle":
+		return SearchTypeArticle, nil
+
+	}
+
+	return SearchTypeUser, errors.New("Can't parse enum")
+}
+
+func SearchTypeFromInt(i int) (SearchType, error) {
+	switch SearchType(i) {
+
+	case 0:
+		return SearchTypeUser, nil
+
+	case 1:
+		return SearchTypePost, nil
+
+	case 2:
+		return SearchTypeArticle, nil
+
+	}
+
+	return SearchTypeUser, errors.New("Can't parse enum")
+}
+
+// -- code --
+// -- end --
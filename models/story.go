@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+	// -- imports --
+	// -- end --
+)
+
+type Story struct {
+	Caption    string    `json:"caption,omitempty" bson:"caption,omitempty"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at" bson:"expires_at"`
+	Id         int       `json:"id" bson:"_id"`
+	Image      string    `json:"image,omitempty" bson:"image,omitempty"`
+	ListId     int       `json:"list_id,omitempty" bson:"list_id,omitempty"`
+	Name       string    `json:"name,omitempty" bson:"name,omitempty"`
+	ProfilePic string    `json:"profile_pic,omitempty" bson:"profile_pic,omitempty"`
+	UserId     int       `json:"user_id" bson:"user_id"`
+	Video      string    `json:"video,omitempty" bson:"video,omitempty"`
+	Viewed     bool      `json:"viewed,omitempty" bson:"viewed,omitempty"`
+	ViewsCount int       `json:"views_count,omitempty" bson:"views_count,omitempty"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *Story) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) StoryFromBody() *Story {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &Story{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid Story")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type StoryListResponse struct {
+	Code   int      `json:"code" bson:"code"`
+	Error  string   `json:"error,omitempty" bson:"error,omitempty"`
+	Result []*Story `json:"result,omitempty" bson:"result,omitempty"`
+	Start  int      `json:"start" bson:"start"`
+	Total  int      `json:"total" bson:"total"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *StoryListResponse) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) StoryListResponseFromBody() *StoryListResponse {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &StoryListResponse{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid StoryListResponse")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
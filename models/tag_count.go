@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type TagCount struct {
+	Count int    `json:"count" bson:"count"`
+	Name  string `json:"name" bson:"name"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *TagCount) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) TagCountFromBody() *TagCount {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &TagCount{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid TagCount")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
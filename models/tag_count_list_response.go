@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type TagCountListResponse struct {
+	Code   int         `json:"code" bson:"code"`
+	Error  string      `json:"error,omitempty" bson:"error,omitempty"`
+	Result []*TagCount `json:"result,omitempty" bson:"result,omitempty"`
+	Start  int         `json:"start" bson:"start"`
+	Total  int         `json:"total" bson:"total"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *TagCountListResponse) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) TagCountListResponseFromBody() *TagCountListResponse {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &TagCountListResponse{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid TagCountListResponse")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	// -- imports --
+	// -- end --
+)
+
+type TargetType int
+
+const (
+	TargetTypePost TargetType = iota
+
+	TargetTypeArticle
+)
+
+func (t TargetType) String() string {
+	return [...]string{"TargetTypePost", "TargetTypeArticle"}[t]
+}
+
+func TargetTypeValues() []TargetType {
+	return []TargetType{TargetTypePost, TargetTypeArticle}
+}
+
+func TargetTypeFromString(s string) (TargetType, error) {
+	switch s {
+
+	case "TargetTypePost":
+		return TargetTypePost, nil
+
+	case "TargetTypeArticle":
+		return TargetTypeArticle, nil
+
+	}
+
+	return TargetTypePost, errors.New("Can't parse enum")
+}
+
+func TargetTypeFromInt(i int) (TargetType, error) {
+	switch TargetType(i) {
+
+	case 0:
+		return TargetTypePost, nil
+
+	case 1:
+		return TargetTypeArticle, nil
+
+	}
+
+	return TargetTypePost, errors.New("Can't parse enum")
+}
+
+// -- code --
+// -- end --
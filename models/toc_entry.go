@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	// -- imports --
+	// -- end --
+)
+
+type TocEntry struct {
+	Anchor string `json:"anchor" bson:"anchor"`
+	Level  int    `json:"level" bson:"level"`
+	Title  string `json:"title" bson:"title"`
+
+	// -- extensions --
+	// -- end --
+}
+
+func (t *TocEntry) Valid() bool {
+	// -- validation --
+	// -- end --
+	return true
+}
+
+func (v *Validator) TocEntryFromBody() *TocEntry {
+	b, err := ioutil.ReadAll(v.r.Body)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	ret := &TocEntry{}
+	err = json.Unmarshal(b, ret)
+	if err != nil {
+		v.Error("body", err.Error())
+		return nil
+	}
+
+	if !ret.Valid() {
+		v.Error("body", "Invalid TocEntry")
+		return nil
+	}
+
+	return ret
+}
+
+// -- code --
+// -- end --
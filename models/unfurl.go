@@ -0,0 +1,105 @@
+package models
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Links in chat messages are unfurled from the page they point to: its
+// Open Graph tags, or failing those its title and meta description. Pages
+// are matched with patterns rather than parsed, which is enough for the
+// head of a page where these tags live.
+
+const (
+	// maxPreviewTitle caps the length of a preview's title.
+	maxPreviewTitle = 300
+	// maxPreviewDescription caps the length of a preview's description.
+	maxPreviewDescription = 1000
+)
+
+var (
+	textUrl   = regexp.MustCompile(`https?://[^\s<>"]+`)
+	htmlMeta  = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	htmlAttr  = regexp.MustCompile(`(?is)([a-z:_-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	htmlTitle = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+// FirstUrl returns the first http or https link in a text, without the
+// punctuation that closes the sentence around it.
+func FirstUrl(s string) string {
+	u := textUrl.FindString(s)
+	u = strings.TrimRight(u, ".,;:!?)]}'")
+	if _, err := url.Parse(u); err != nil {
+		return ""
+	}
+	return u
+}
+
+// ParseLinkPreview reads the preview of the page at page from its HTML. It
+// returns nil if the page has neither a title, a description nor an image.
+func ParseLinkPreview(page *url.URL, b []byte) *LinkPreview {
+	meta := make(map[string]string)
+	for _, tag := range htmlMeta.FindAll(b, -1) {
+		attrs := make(map[string]string)
+		for _, m := range htmlAttr.FindAllSubmatch(tag, -1) {
+			attrs[strings.ToLower(string(m[1]))] = string(m[2]) + string(m[3]) + string(m[4])
+		}
+		key := strings.ToLower(attrs["property"])
+		if key == "" {
+			key = strings.ToLower(attrs["name"])
+		}
+		// the first of repeated tags is the main one
+		if _, ok := meta[key]; key != "" && !ok {
+			meta[key] = previewText(attrs["content"])
+		}
+	}
+
+	p := &LinkPreview{
+		Url:         page.String(),
+		Title:       firstOf(meta["og:title"], meta["twitter:title"]),
+		Description: firstOf(meta["og:description"], meta["twitter:description"], meta["description"]),
+		SiteName:    meta["og:site_name"],
+	}
+	if p.Title == "" {
+		if m := htmlTitle.FindSubmatch(b); m != nil {
+			p.Title = previewText(string(m[1]))
+		}
+	}
+
+	// images are only kept if they can be loaded as they are
+	if img := firstOf(meta["og:image"], meta["og:image:url"], meta["twitter:image"]); img != "" {
+		if u, err := page.Parse(img); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+			p.Image = u.String()
+		}
+	}
+
+	p.Title = truncateText(p.Title, maxPreviewTitle)
+	p.Description = truncateText(p.Description, maxPreviewDescription)
+	if p.Title == "" && p.Description == "" && p.Image == "" {
+		return nil
+	}
+	return p
+}
+
+// previewText turns the text of a tag into plain text on a single line.
+func previewText(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+func truncateText(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
+
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
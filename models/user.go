@@ -8,15 +8,22 @@ import (
 )
 
 type User struct {
-	Email      string    `json:"email" bson:"email"`
-	Id         int       `json:"id" bson:"_id"`
-	Name       string    `json:"name" bson:"name"`
-	Password   string    `json:"password" bson:"password"`
-	Phone      string    `json:"phone,omitempty" bson:"phone,omitempty"`
-	ProfilePic string    `json:"profile_pic,omitempty" bson:"profile_pic,omitempty"`
-	ReqId      int       `json:"req_id,omitempty" bson:"req_id,omitempty"`
-	Status     ReqStatus `json:"status,omitempty" bson:"status,omitempty"`
-	Verified   bool      `json:"verified" bson:"verified"`
+	Email              string    `json:"email" bson:"email"`
+	FollowPending      bool      `json:"follow_pending,omitempty" bson:"follow_pending,omitempty"`
+	Followed           bool      `json:"followed,omitempty" bson:"followed,omitempty"`
+	FollowersCount     int       `json:"followers_count,omitempty" bson:"followers_count,omitempty"`
+	FollowingCount     int       `json:"following_count,omitempty" bson:"following_count,omitempty"`
+	Id                 int       `json:"id" bson:"_id"`
+	MutualAvatars      []string  `json:"mutual_avatars,omitempty" bson:"mutual_avatars,omitempty"`
+	MutualFriendsCount int       `json:"mutual_friends_count,omitempty" bson:"mutual_friends_count,omitempty"`
+	Name               string    `json:"name" bson:"name"`
+	Password           string    `json:"password" bson:"password"`
+	Phone              string    `json:"phone,omitempty" bson:"phone,omitempty"`
+	Private            bool      `json:"private,omitempty" bson:"private,omitempty"`
+	ProfilePic         string    `json:"profile_pic,omitempty" bson:"profile_pic,omitempty"`
+	ReqId              int       `json:"req_id,omitempty" bson:"req_id,omitempty"`
+	Status             ReqStatus `json:"status,omitempty" bson:"status,omitempty"`
+	Verified           bool      `json:"verified" bson:"verified"`
 
 	// -- extensions --
 	// -- end --
@@ -252,6 +252,256 @@ func (v *Values) ReqStatusArray() []ReqStatus {
 	return ret
 }
 
+func (v *Values) PostKind() PostKind {
+	ret, err := PostKindFromInt(v.Int())
+	if err != nil {
+		v.v.Error(v.name, err.Error())
+	}
+	return ret
+}
+
+func (v *Values) PostKindArray() []PostKind {
+	ints := v.IntArray()
+	if ints == nil {
+		return nil
+	}
+	var ret []PostKind
+	for _, i := range ints {
+		val, err := PostKindFromInt(i)
+		if err != nil {
+			v.v.Error(v.name, err.Error())
+			return nil
+		}
+		ret = append(ret, val)
+	}
+	return ret
+}
+
+func (v *Values) TargetType() TargetType {
+	ret, err := TargetTypeFromInt(v.Int())
+	if err != nil {
+		v.v.Error(v.name, err.Error())
+	}
+	return ret
+}
+
+func (v *Values) TargetTypeArray() []TargetType {
+	ints := v.IntArray()
+	if ints == nil {
+		return nil
+	}
+	var ret []TargetType
+	for _, i := range ints {
+		val, err := TargetTypeFromInt(i)
+		if err != nil {
+			v.v.Error(v.name, err.Error())
+			return nil
+		}
+		ret = append(ret, val)
+	}
+	return ret
+}
+
+func (v *Values) PublishStatus() PublishStatus {
+	ret, err := PublishStatusFromInt(v.Int())
+	if err != nil {
+		v.v.Error(v.name, err.Error())
+	}
+	return ret
+}
+
+func (v *Values) PublishStatusArray() []PublishStatus {
+	ints := v.IntArray()
+	if ints == nil {
+		return nil
+	}
+	var ret []PublishStatus
+	for _, i := range ints {
+		val, err := PublishStatusFromInt(i)
+		if err != nil {
+			v.v.Error(v.name, err.Error())
+			return nil
+		}
+		ret = append(ret, val)
+	}
+	return ret
+}
+
+func (v *Values) SearchType() SearchType {
+	ret, err := SearchTypeFromInt(v.Int())
+	if err != nil {
+		v.v.Error(v.name, err.Error())
+	}
+	return ret
+}
+
+func (v *Values) SearchTypeArray() []SearchType {
+	ints := v.IntArray()
+	if ints == nil {
+		return nil
+	}
+	var ret []SearchType
+	for _, i := range ints {
+		val, err := SearchTypeFromInt(i)
+		if err != nil {
+			v.v.Error(v.name, err.Error())
+			return nil
+		}
+		ret = append(ret, val)
+	}
+	return ret
+}
+
+func (v *Values) Visibility() Visibility {
+	ret, err := VisibilityFromInt(v.Int())
+	if err != nil {
+		v.v.Error(v.name, err.Error())
+	}
+	return ret
+}
+
+func (v *Values) VisibilityArray() []Visibility {
+	ints := v.IntArray()
+	if ints == nil {
+		return nil
+	}
+	var ret []Visibility
+	for _, i := range ints {
+		val, err := VisibilityFromInt(i)
+		if err != nil {
+			v.v.Error(v.name, err.Error())
+			return nil
+		}
+		ret = append(ret, val)
+	}
+	return ret
+}
+
+func (v *Values) FriendEventType() FriendEventType {
+	ret, err := FriendEventTypeFromInt(v.Int())
+	if err != nil {
+		v.v.Error(v.name, err.Error())
+	}
+	return ret
+}
+
+func (v *Values) FriendEventTypeArray() []FriendEventType {
+	ints := v.IntArray()
+	if ints == nil {
+		return nil
+	}
+	var ret []FriendEventType
+	for _, i := range ints {
+		val, err := FriendEventTypeFromInt(i)
+		if err != nil {
+			v.v.Error(v.name, err.Error())
+			return nil
+		}
+		ret = append(ret, val)
+	}
+	return ret
+}
+
+func (v *Values) ChatEventType() ChatEventType {
+	ret, err := ChatEventTypeFromInt(v.Int())
+	if err != nil {
+		v.v.Error(v.name, err.Error())
+	}
+	return ret
+}
+
+func (v *Values) ChatEventTypeArray() []ChatEventType {
+	ints := v.IntArray()
+	if ints == nil {
+		return nil
+	}
+	var ret []ChatEventType
+	for _, i := range ints {
+		val, err := ChatEventTypeFromInt(i)
+		if err != nil {
+			v.v.Error(v.name, err.Error())
+			return nil
+		}
+		ret = append(ret, val)
+	}
+	return ret
+}
+
+func (v *Values) ConversationRole() ConversationRole {
+	ret, err := ConversationRoleFromInt(v.Int())
+	if err != nil {
+		v.v.Error(v.name, err.Error())
+	}
+	return ret
+}
+
+func (v *Values) ConversationRoleArray() []ConversationRole {
+	ints := v.IntArray()
+	if ints == nil {
+		return nil
+	}
+	var ret []ConversationRole
+	for _, i := range ints {
+		val, err := ConversationRoleFromInt(i)
+		if err != nil {
+			v.v.Error(v.name, err.Error())
+			return nil
+		}
+		ret = append(ret, val)
+	}
+	return ret
+}
+
+func (v *Values) ChatKind() ChatKind {
+	ret, err := ChatKindFromInt(v.Int())
+	if err != nil {
+		v.v.Error(v.name, err.Error())
+	}
+	return ret
+}
+
+func (v *Values) ChatKindArray() []ChatKind {
+	ints := v.IntArray()
+	if ints == nil {
+		return nil
+	}
+	var ret []ChatKind
+	for _, i := range ints {
+		val, err := ChatKindFromInt(i)
+		if err != nil {
+			v.v.Error(v.name, err.Error())
+			return nil
+		}
+		ret = append(ret, val)
+	}
+	return ret
+}
+
+func (v *Values) AttachmentType() AttachmentType {
+	ret, err := AttachmentTypeFromInt(v.Int())
+	if err != nil {
+		v.v.Error(v.name, err.Error())
+	}
+	return ret
+}
+
+func (v *Values) AttachmentTypeArray() []AttachmentType {
+	ints := v.IntArray()
+	if ints == nil {
+		return nil
+	}
+	var ret []AttachmentType
+	for _, i := range ints {
+		val, err := AttachmentTypeFromInt(i)
+		if err != nil {
+			v.v.Error(v.name, err.Error())
+			return nil
+		}
+		ret = append(ret, val)
+	}
+	return ret
+}
+
 // -- more-values --
 // -- end --
 
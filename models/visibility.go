@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	// -- imports --
+	// -- end --
+)
+
+type Visibility int
+
+const (
+	VisibilityPublic Visibility = iota
+
+	VisibilityFriends
+
+	VisibilityList
+)
+
+func (v Visibility) String() string {
+	return [...]string{"VisibilityPublic", "VisibilityFriends", "VisibilityList"}[v]
+}
+
+func VisibilityValues() []Visibility {
+	return []Visibility{VisibilityPublic, VisibilityFriends, VisibilityList}
+}
+
+func VisibilityFromString(s string) (Visibility, error) {
+	switch s {
+
+	case "VisibilityPublic":
+		return VisibilityPublic, nil
+
+	case "VisibilityFriends":
+		return VisibilityFriends, nil
+
+	case "VisibilityList":
+		return VisibilityList, nil
+
+	}
+
+	return VisibilityPublic, errors.New("Can't parse enum")
+}
+
+func VisibilityFromInt(i int) (Visibility, error) {
+	switch Visibility(i) {
+
+	case 0:
+		return VisibilityPublic, nil
+
+	case 1:
+		return VisibilityFriends, nil
+
+	case 2:
+		return VisibilityList, nil
+
+	}
+
+	return VisibilityPublic, errors.New("Can't parse enum")
+}
+
+// -- code --
+// -- end --
@@ -0,0 +1,61 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// AcceptFollowRequest
+func AcceptFollowRequest(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "acceptFollowRequest"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		var f models.Follow
+		err := mongoDb.Collection("follows").FindOneAndUpdate(r.Context(), bson.M{
+			"_id":     id,
+			"to_id":   userId,
+			"pending": true,
+		}, bson.M{"$unset": bson.M{"pending": ""}}).Decode(&f)
+		if err == mongo.ErrNoDocuments {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Error("Unable to accept follow request", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		countFollow(r.Context(), mongoDb, f.FromId, f.ToId, 1)
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -1,6 +1,8 @@
 package operations
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -8,6 +10,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 	// -- imports --
 	// -- end --
@@ -18,6 +21,13 @@ func AcceptFriendRequest(sugar string, mongoDb *mongo.Database, logger *zap.Logg
 	oLog := logger.With(zap.String("op", "acceptFriendRequest"))
 	// -- init --
 	frId, _ := models.NewIDNode(9)
+	if mongoDb != nil {
+		_, err := mongoDb.Collection("friends").Indexes().CreateOne(context.Background(), friendPairIndex)
+		if err != nil {
+			oLog.Error("Unable to create friends index", zap.Error(err))
+		}
+		checkTransactions(mongoDb, oLog)
+	}
 	// -- end --
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		v := models.NewValidator(r).Secret(sugar)
@@ -36,27 +46,81 @@ func AcceptFriendRequest(sugar string, mongoDb *mongo.Database, logger *zap.Logg
 
 		// get the friend request
 
-		var fr models.FriendRequest
-		err := mongoDb.Collection("friend_requests").FindOne(r.Context(), bson.M{"_id": id}).Decode(&fr)
+		fr, ok := findFriendRequest(w, r, mongoDb, id, userId, true)
+		if !ok {
+			return
+		}
+
+		// the request is used up and the friendship created together, so a
+		// request is never left behind once accepted, nor a friendship
+		// without one
+		sess, err := mongoDb.Client().StartSession()
 		if err != nil {
+			log.Error("Unable to start session", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
-		// add the friend
-		newFr := models.FriendEntry{
-			CreatedAt: time.Now(),
-			FromId:    fr.FromId,
-			ToId:      fr.ToId,
-			Id:        int(frId.Generate().Int64()),
-		}
+		defer sess.EndSession(r.Context())
+
+		_, err = sess.WithTransaction(r.Context(), func(sc mongo.SessionContext) (interface{}, error) {
+			res, err := mongoDb.Collection("friend_requests").DeleteOne(sc, bson.M{"_id": fr.Id, "to_id": userId})
+			if err != nil {
+				return nil, err
+			}
+			if res.DeletedCount == 0 {
+				return nil, errRequestGone
+			}
+
+			// the request stays if the users are friends already
+			friends, err := areFriends(sc, mongoDb, fr.FromId, fr.ToId)
+			if err != nil {
+				return nil, err
+			}
+			if friends {
+				return nil, errAlreadyFriends
+			}
 
-		_, err = mongoDb.Collection("friends").InsertOne(r.Context(), newFr)
+			// add the friend
+			newFr := models.FriendEntry{
+				CreatedAt: time.Now(),
+				FromId:    fr.FromId,
+				ToId:      fr.ToId,
+				Pair:      models.FriendPair(fr.FromId, fr.ToId),
+				Id:        int(frId.Generate().Int64()),
+			}
+
+			_, err = mongoDb.Collection("friends").InsertOne(sc, newFr)
+			return nil, err
+		})
+		if err == errAlreadyFriends {
+			JSON(&models.StatusResponse{
+				Code:  409,
+				Error: "Already friends",
+			}, w)
+			return
+		}
+		if err == errRequestGone || models.IsDuplicateKeyError(err) {
+			JSON(&models.StatusResponse{
+				Code:  409,
+				Error: "Request already handled",
+			}, w)
+			return
+		}
+		if isNoTransactionsError(err) {
+			log.Error("Accepting friend requests needs MongoDB to run as a replica set or sharded cluster", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
 		if err != nil {
+			log.Error("Unable to accept friend request", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
+		notifyFriends(r.Context(), mongoDb, models.FriendEventTypeAccepted, fr.FromId, userId, fr.Id)
+		notifyFriends(r.Context(), mongoDb, models.FriendEventTypePending, userId, 0, 0)
+
 		JSON(&models.StatusResponse{
 			Code: 200,
 		}, w)
@@ -65,4 +129,71 @@ func AcceptFriendRequest(sugar string, mongoDb *mongo.Database, logger *zap.Logg
 }
 
 // -- extra --
+
+var (
+	// errRequestGone is returned when a friend request was handled
+	// meanwhile.
+	errRequestGone = errors.New("friend request gone")
+	// errAlreadyFriends is returned when the users of a friend request are
+	// friends already.
+	errAlreadyFriends = errors.New("already friends")
+)
+
+// isNoTransactionsError reports whether err comes from a deployment that
+// can't run transactions, such as a standalone server.
+func isNoTransactionsError(err error) bool {
+	e, ok := err.(mongo.CommandError)
+	return ok && (e.Code == 20 || e.Code == 263)
+}
+
+// checkTransactions logs an error at startup if the deployment can't run
+// the transactions some operations need.
+func checkTransactions(mongoDb *mongo.Database, log *zap.Logger) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := mongoDb.RunCommand(context.Background(), bson.D{{Key: "isMaster", Value: 1}}).Decode(&hello); err != nil {
+		log.Error("Unable to check the MongoDB deployment", zap.Error(err))
+		return
+	}
+	if hello.SetName == "" && hello.Msg != "isdbgrid" {
+		log.Error("MongoDB is a standalone server, which can't run transactions: accepting friend requests and voting in polls will fail")
+	}
+}
+
+// friendPairIndex keeps a single friendship or friend request per pair of
+// users. Entries from before pairs were stored aren't covered.
+var friendPairIndex = mongo.IndexModel{
+	Keys: bson.D{{Key: "pair", Value: 1}},
+	Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+		"pair": bson.M{"$exists": true},
+	}),
+}
+
+// findFriendRequest finds the friend request and checks the user takes part
+// in it, as its recipient or otherwise as its sender. The response is
+// written when it can't be used.
+func findFriendRequest(w http.ResponseWriter, r *http.Request, mongoDb *mongo.Database, id, userId int, recipient bool) (*models.FriendRequest, bool) {
+	var fr models.FriendRequest
+	if err := mongoDb.Collection("friend_requests").FindOne(r.Context(), bson.M{"_id": id}).Decode(&fr); err != nil {
+		if err == mongo.ErrNoDocuments {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	if fr.FromId != userId && fr.ToId != userId {
+		w.WriteHeader(http.StatusNotFound)
+		return nil, false
+	}
+	if (recipient && fr.ToId != userId) || (!recipient && fr.FromId != userId) {
+		w.WriteHeader(http.StatusForbidden)
+		return nil, false
+	}
+	return &fr, true
+}
+
 // -- end --
@@ -2,8 +2,8 @@ package operations
 
 import (
 	"net/http"
-	"time"
 
+	"fr_book_api/actors"
 	"fr_book_api/models"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,18 +16,25 @@ import (
 func AddChat(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
 	oLog := logger.With(zap.String("op", "addChat"))
 	// -- init --
-
-	chatId, _ := models.NewIDNode(8)
-
 	// -- end --
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		v := models.NewValidator(r).Secret(sugar)
 
 		userId := v.Token("user_id").Int()
 
-		toId := v.Form("to_id").Int()
+		toId := v.Form("to_id").Optional().Int()
+
+		conversationId := v.Form("conversation_id").Optional().Int()
+
+		content := v.Form("content").Optional().String()
+
+		attachments := v.Form("attachments").Optional().StringArray()
 
-		content := v.Form("content").String()
+		attachmentNames := v.Form("attachment_names").Optional().StringArray()
+
+		duration := v.Form("duration").Optional().Float()
+
+		waveform := v.Form("waveform").Optional().IntArray()
 
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
 		// -- code --
@@ -35,19 +42,34 @@ func AddChat(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Han
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("to_id", toId), zap.Any("content", content))
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("to_id", toId), zap.Any("conversation_id", conversationId), zap.Any("content", content), zap.Any("attachments", attachments))
 
-		if content == "" {
-			return
+		// the duration and waveform are those of the voice note among the
+		// attachments
+		var atts []*models.ChatAttachment
+		for i, f := range attachments {
+			a := &models.ChatAttachment{
+				File:     f,
+				Duration: duration,
+				Waveform: waveform,
+			}
+			if i < len(attachmentNames) {
+				a.Name = attachmentNames[i]
+			}
+			atts = append(atts, a)
 		}
 
-		mongoDb.Collection("chats").InsertOne(r.Context(), &models.Chat{
-			Id:        int(chatId.Generate().Int64()),
-			FromId:    userId,
-			ToId:      toId,
-			Content:   content,
-			CreatedAt: time.Now(),
-		})
+		// the hub stores the message and delivers it to every session of
+		// everyone in the conversation
+		if _, ok := toChatHub(w, log, userId, &models.ChatEvent{
+			Kind:           models.ChatEventTypeSend,
+			ToId:           toId,
+			ConversationId: conversationId,
+			Content:        content,
+			Attachments:    atts,
+		}); !ok {
+			return
+		}
 
 		JSON(&models.StatusResponse{
 			Code: 200,
@@ -57,4 +79,43 @@ func AddChat(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Han
 }
 
 // -- extra --
+
+// chatTimeout is how many seconds a change waits for the chat hub.
+const chatTimeout = 10
+
+// toChatHub has the chat hub make a change for the user and returns the
+// hub's answer, writing the error response if the change failed.
+func toChatHub(w http.ResponseWriter, log *zap.Logger, userId int, ev *models.ChatEvent) (*models.ChatEvent, bool) {
+	hb := actors.HubById("chat")
+	if hb == nil {
+		log.Error("Chat hub not running")
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+
+	c := actors.NewOneTimeClient(1)
+	c.UserID = userId
+	hb.Default(ev, c)
+
+	res, err := c.Read(chatTimeout)
+	if err != nil {
+		log.Error("No answer from chat hub", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	answer, ok := res.(*models.ChatEvent)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	if answer.Kind == models.ChatEventTypeError {
+		JSON(&models.StatusResponse{
+			Code:  400,
+			Error: answer.Error,
+		}, w)
+		return nil, false
+	}
+	return answer, true
+}
+
 // -- end --
@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -24,23 +25,62 @@ func AddComment(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.
 
 		userId := v.Token("user_id").Int()
 
+		target := v.Path("target").String()
+
 		id := v.Path("id").Int()
 
 		content := v.Form("content").String()
 
+		parentId := v.Form("parent_id").Optional().Int()
+
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
 		// -- code --
 		if !v.Valid() {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("content", content))
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("target", target), zap.Any("id", id), zap.Any("content", content))
 
 		if len(content) == 0 {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
 
+		targetType, ok := targetFromPath(target)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		ownerId, ok, err := targetOwner(r.Context(), mongoDb, targetType, id, userId)
+		if err != nil {
+			log.Error("Unable to get target", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// replies to replies join the thread of the comment they answer
+		if parentId != 0 {
+			filter := commentFilter(targetType, id)
+			filter["_id"] = parentId
+			var parent models.Comment
+			if err := mongoDb.Collection("comments").FindOne(r.Context(), filter).Decode(&parent); err != nil {
+				if err == mongo.ErrNoDocuments {
+					w.WriteHeader(http.StatusNotFound)
+				} else {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				return
+			}
+			if parent.ParentId != 0 {
+				parentId = parent.ParentId
+			}
+		}
+
 		var u models.User
 
 		if err := mongoDb.Collection("users").FindOne(r.Context(), bson.M{"_id": userId}).Decode(&u); err != nil {
@@ -53,17 +93,35 @@ func AddComment(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.
 		}
 
 		comment := models.Comment{
-			PostId:    id,
-			UserId:    userId,
-			Content:   content,
-			Name:      u.Name,
-			CreatedAt: time.Now(),
-			Id:        int(commentId.Generate().Int64()),
+			TargetType: targetType,
+			TargetId:   id,
+			ParentId:   parentId,
+			UserId:     userId,
+			Content:    content,
+			Name:       u.Name,
+			CreatedAt:  time.Now(),
+			Id:         int(commentId.Generate().Int64()),
+		}
+		if targetType == models.TargetTypePost {
+			comment.PostId = id
 		}
 
-		mongoDb.Collection("comments").InsertOne(r.Context(), comment)
+		if _, err := mongoDb.Collection("comments").InsertOne(r.Context(), comment); err != nil {
+			log.Error("Unable to insert comment", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if parentId != 0 {
+			mongoDb.Collection("comments").UpdateOne(r.Context(), bson.M{"_id": parentId}, bson.M{"$inc": bson.M{"replies_count": 1}})
+		}
 
-		mongoDb.Collection("posts").UpdateOne(r.Context(), bson.M{"_id": id}, bson.M{"$inc": bson.M{"comments_count": 1}})
+		mongoDb.Collection(targetCollection(targetType)).UpdateOne(r.Context(), bson.M{"_id": id}, bson.M{"$inc": bson.M{"comments_count": 1}})
+
+		if targetType == models.TargetTypePost {
+			refreshRank(r.Context(), mongoDb, id)
+		}
+		bumpAffinity(r.Context(), mongoDb, userId, ownerId, "interactions")
 
 		JSON(&models.StatusResponse{
 			Code: 200,
@@ -73,4 +131,57 @@ func AddComment(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.
 }
 
 // -- extra --
+
+// targetFromPath maps the collection named in a route to its target type.
+func targetFromPath(s string) (models.TargetType, bool) {
+	for _, t := range models.TargetTypeValues() {
+		if targetCollection(t) == s {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// targetOwner looks up who wrote the target a user interacts with. It
+// reports false when the target doesn't exist or the user can't see it,
+// which includes targets of users blocked either way.
+func targetOwner(ctx context.Context, mongoDb *mongo.Database, t models.TargetType, id, userId int) (int, bool, error) {
+	var target struct {
+		UserId     int                  `bson:"user_id"`
+		Status     models.PublishStatus `bson:"status"`
+		Visibility models.Visibility    `bson:"visibility"`
+		ListId     int                  `bson:"list_id"`
+	}
+	err := mongoDb.Collection(targetCollection(t)).FindOne(ctx, bson.M{"_id": id}).Decode(&target)
+	if err == mongo.ErrNoDocuments {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if isUnpublished(target.Status) && target.UserId != userId {
+		return 0, false, nil
+	}
+	if target.UserId != userId {
+		blocked, err := blockedBetween(ctx, mongoDb, target.UserId, userId)
+		if err != nil || blocked {
+			return 0, false, err
+		}
+	}
+	ok, err := inAudience(ctx, mongoDb, target.UserId, userId, target.Visibility, target.ListId)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	return target.UserId, true, nil
+}
+
+// commentFilter matches the comments of a target. Post comments predate
+// targets and are found by their post.
+func commentFilter(t models.TargetType, id int) bson.M {
+	if t == models.TargetTypePost {
+		return bson.M{"post_id": id}
+	}
+	return bson.M{"target_type": t, "target_id": id}
+}
+
 // -- end --
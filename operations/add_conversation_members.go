@@ -0,0 +1,112 @@
+package operations
+
+import (
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"github.com/thoas/go-funk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// AddConversationMembers
+func AddConversationMembers(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "addConversationMembers"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		memberIds := v.Form("member_ids").IntArray()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("member_ids", memberIds))
+
+		conv, ok := findGroup(w, r, mongoDb, id, userId, true)
+		if !ok {
+			return
+		}
+
+		var fresh []int
+		for _, m := range memberIds {
+			if !funk.Contains(conv.ParticipantIds, m) {
+				fresh = append(fresh, m)
+			}
+		}
+
+		added, ok, err := groupMembers(r.Context(), mongoDb, userId, fresh)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok || len(conv.ParticipantIds)+len(added) > maxGroupMembers {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Only friends can be added",
+			}, w)
+			return
+		}
+
+		if len(added) > 0 {
+			now := time.Now()
+			var members []*models.ConversationMember
+			for _, m := range added {
+				members = append(members, &models.ConversationMember{
+					UserId:   m,
+					JoinedAt: &now,
+				})
+			}
+
+			// nobody added in the meantime gets added twice
+			err := mongoDb.Collection("conversations").FindOneAndUpdate(r.Context(), bson.M{
+				"_id":             id,
+				"participant_ids": bson.M{"$nin": added},
+			}, bson.M{"$push": bson.M{
+				"participant_ids": bson.M{"$each": added},
+				"members":         bson.M{"$each": members},
+			}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(conv)
+			if err == mongo.ErrNoDocuments {
+				JSON(&models.StatusResponse{
+					Code:  409,
+					Error: "Already a member",
+				}, w)
+				return
+			}
+			if err != nil {
+				log.Error("Unable to add group members", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			for _, m := range added {
+				announceGroup(id, userId, models.ChatKindJoined, m, "")
+			}
+		}
+
+		viewConversation(r.Context(), mongoDb, make(map[int]models.User), conv, userId)
+
+		JSON(&models.ConversationResponse{
+			Code:   200,
+			Result: conv,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -1,8 +1,11 @@
 package operations
 
 import (
+	"context"
 	"net/http"
+	"time"
 
+	"fr_book_api/actors"
 	"fr_book_api/models"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -17,6 +20,12 @@ func AddFriendRequest(sugar string, mongoDb *mongo.Database, logger *zap.Logger)
 	oLog := logger.With(zap.String("op", "addFriendRequest"))
 	// -- init --
 	frId, _ := models.NewIDNode(10)
+	if mongoDb != nil {
+		_, err := mongoDb.Collection("friend_requests").Indexes().CreateOne(context.Background(), friendPairIndex)
+		if err != nil {
+			oLog.Error("Unable to create friend requests index", zap.Error(err))
+		}
+	}
 	// -- end --
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		v := models.NewValidator(r).Secret(sugar)
@@ -33,6 +42,36 @@ func AddFriendRequest(sugar string, mongoDb *mongo.Database, logger *zap.Logger)
 		}
 		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("to_id", toId))
 
+		if toId == userId {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		friends, err := areFriends(r.Context(), mongoDb, userId, toId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if friends {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Already friends",
+			}, w)
+			return
+		}
+
+		blocked, err := blockedBetween(r.Context(), mongoDb, userId, toId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if blocked {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
 		// count existing friend requests
 
 		count, err := mongoDb.Collection("friend_requests").CountDocuments(r.Context(), bson.M{
@@ -52,17 +91,27 @@ func AddFriendRequest(sugar string, mongoDb *mongo.Database, logger *zap.Logger)
 			return
 		}
 
+		reqId := int(frId.Generate().Int64())
 		_, err = mongoDb.Collection("friend_requests").InsertOne(r.Context(), &models.FriendRequest{
-			FromId: userId,
-			ToId:   toId,
-			Id:     int(frId.Generate().Int64()),
+			FromId:    userId,
+			ToId:      toId,
+			Pair:      models.FriendPair(userId, toId),
+			CreatedAt: time.Now(),
+			Id:        reqId,
 		})
 
+		if models.IsDuplicateKeyError(err) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
+		notifyFriends(r.Context(), mongoDb, models.FriendEventTypeRequest, toId, userId, reqId)
+
 		JSON(&models.StatusResponse{
 			Code: 200,
 		}, w)
@@ -71,4 +120,30 @@ func AddFriendRequest(sugar string, mongoDb *mongo.Database, logger *zap.Logger)
 }
 
 // -- extra --
+
+// notifyFriends pushes a friend event about fromId to toId through the
+// notifier hub, which adds how many requests now wait on toId, so that
+// every connected session can update its badge.
+func notifyFriends(ctx context.Context, mongoDb *mongo.Database, kind models.FriendEventType, toId, fromId, requestId int) {
+	hb := actors.HubById("notifier")
+	if hb == nil {
+		return
+	}
+
+	ev := &models.FriendEvent{
+		Kind:      kind,
+		ToId:      toId,
+		UserId:    fromId,
+		RequestId: requestId,
+	}
+	if fromId != 0 {
+		var u models.User
+		if err := mongoDb.Collection("users").FindOne(ctx, bson.M{"_id": fromId}).Decode(&u); err == nil {
+			ev.Name = u.Name
+			ev.ProfilePic = u.ProfilePic
+		}
+	}
+	hb.Default(ev, nil)
+}
+
 // -- end --
@@ -0,0 +1,116 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// BlockUser
+func BlockUser(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "blockUser"))
+	// -- init --
+	blockId, _ := models.NewIDNode(17)
+	if mongoDb != nil {
+		_, err := mongoDb.Collection("blocks").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "from_id", Value: 1}, {Key: "to_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			oLog.Error("Unable to create blocks index", zap.Error(err))
+		}
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		if id == userId {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Can't block yourself",
+			}, w)
+			return
+		}
+
+		count, err := mongoDb.Collection("users").CountDocuments(r.Context(), bson.M{"_id": id})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if count == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, err = mongoDb.Collection("blocks").InsertOne(r.Context(), &models.Block{
+			Id:        int(blockId.Generate().Int64()),
+			FromId:    userId,
+			ToId:      id,
+			CreatedAt: time.Now(),
+		})
+		if err != nil && !models.IsDuplicateKeyError(err) {
+			log.Error("Unable to block user", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		// blocking ends the friendship, follows and any request between the two
+		between := bson.M{"$or": []bson.M{
+			{"from_id": userId, "to_id": id},
+			{"from_id": id, "to_id": userId},
+		}}
+		for _, coll := range []string{"friends", "friend_requests"} {
+			if _, err := mongoDb.Collection(coll).DeleteMany(r.Context(), between); err != nil {
+				log.Error("Unable to remove "+coll, zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		notifyFriends(r.Context(), mongoDb, models.FriendEventTypePending, userId, 0, 0)
+		notifyFriends(r.Context(), mongoDb, models.FriendEventTypePending, id, 0, 0)
+
+		for _, f := range [][2]int{{userId, id}, {id, userId}} {
+			if err := dropFollow(r.Context(), mongoDb, f[0], f[1]); err != nil {
+				log.Error("Unable to remove follow", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		if err := leaveFriendLists(r.Context(), mongoDb, userId, id); err != nil {
+			log.Error("Unable to update friend lists", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
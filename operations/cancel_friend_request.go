@@ -0,0 +1,56 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// CancelFriendRequest
+func CancelFriendRequest(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "cancelFriendRequest"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		fr, ok := findFriendRequest(w, r, mongoDb, id, userId, false)
+		if !ok {
+			return
+		}
+
+		_, err := mongoDb.Collection("friend_requests").DeleteOne(r.Context(), bson.M{"_id": id, "from_id": userId})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		notifyFriends(r.Context(), mongoDb, models.FriendEventTypeCancelled, fr.ToId, userId, fr.Id)
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -2,6 +2,9 @@ package operations
 
 import (
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -38,6 +41,10 @@ func CreateArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 
 		pdf := v.Form("pdf").Optional().String()
 
+		status := v.Form("status").Optional().String()
+
+		publishAt := v.Form("publish_at").Optional().DateTime()
+
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
 		// -- code --
 		if !v.Valid() {
@@ -46,16 +53,45 @@ func CreateArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 		}
 		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("title", title), zap.Any("content", content), zap.Any("photo", photo))
 		ct := time.Now()
+		aStatus, aAt, ok := publishState(status, publishAt, ct)
+		if strings.EqualFold(strings.TrimSpace(status), "review") {
+			aStatus, aAt, ok = models.PublishStatusReview, nil, true
+		}
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if len(content) > models.MaxMarkdownLength {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Content too long",
+			}, w)
+			return
+		}
+
+		if pdf != "" && !isPdfAsset(pdf) {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Invalid PDF",
+			}, w)
+			return
+		}
+
 		article := &models.Article{
 			Content:     content,
 			Photo:       photo,
-			Tags:        strings.Split(tags, ","),
+			Tags:        models.NormalizeTags(tags),
 			AuthorName:  authorName,
 			Description: description,
 			CreatedAt:   &ct,
 			Title:       title,
 			UserId:      userId,
 			Pdf:         pdf,
+			Status:      aStatus,
+			PublishAt:   aAt,
+			PublishedAt: publishedAt(aStatus, ct),
+			Revision:    1,
 			Id:          int(articlesId.Generate().Int64()),
 		}
 
@@ -66,6 +102,10 @@ func CreateArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 			return
 		}
 
+		if err := firstRevision(r.Context(), mongoDb, article, ct); err != nil {
+			log.Error("Unable to insert revision", zap.Error(err))
+		}
+
 		JSON(&models.StatusResponse{
 			Code: 200,
 		}, w)
@@ -74,4 +114,27 @@ func CreateArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 }
 
 // -- extra --
+
+// isPdfAsset checks that an uploaded file is a PDF.
+func isPdfAsset(name string) bool {
+	b, err := os.ReadFile(assetPath(name))
+	return err == nil && models.IsPdf(b)
+}
+
+// assetPath is where an uploaded file named by an asset name or URL is
+// stored.
+func assetPath(name string) string {
+	if u, err := url.Parse(name); err == nil {
+		name = u.Path
+	}
+	return filepath.Join("assets", filepath.Base(name))
+}
+
+func publishedAt(s models.PublishStatus, ct time.Time) *time.Time {
+	if s != models.PublishStatusPublished {
+		return nil
+	}
+	return &ct
+}
+
 // -- end --
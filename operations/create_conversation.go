@@ -0,0 +1,178 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"fr_book_api/actors"
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// CreateConversation
+func CreateConversation(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "createConversation"))
+	// -- init --
+
+	conversationId, _ := models.NewIDNode(23)
+
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		title := v.Form("title").String()
+
+		avatar := v.Form("avatar").Optional().String()
+
+		memberIds := v.Form("member_ids").IntArray()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("title", title), zap.Any("member_ids", memberIds))
+
+		title = strings.TrimSpace(title)
+		if title == "" || len(title) > maxGroupTitle {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		members, ok, err := groupMembers(r.Context(), mongoDb, userId, memberIds)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok || len(members) == 0 || len(members) >= maxGroupMembers {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Groups can only be started with friends",
+			}, w)
+			return
+		}
+
+		now := time.Now()
+		conv := &models.Conversation{
+			Id:             int(conversationId.Generate().Int64()),
+			Group:          true,
+			Title:          title,
+			Avatar:         avatar,
+			CreatedBy:      userId,
+			ParticipantIds: append([]int{userId}, members...),
+			Members: []*models.ConversationMember{{
+				UserId:   userId,
+				Role:     models.ConversationRoleAdmin,
+				JoinedAt: &now,
+			}},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		for _, id := range members {
+			conv.Members = append(conv.Members, &models.ConversationMember{
+				UserId:   id,
+				JoinedAt: &now,
+			})
+		}
+
+		if _, err := mongoDb.Collection("conversations").InsertOne(r.Context(), conv); err != nil {
+			log.Error("Unable to create group", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		for _, id := range members {
+			announceGroup(conv.Id, userId, models.ChatKindJoined, id, "")
+		}
+
+		viewConversation(r.Context(), mongoDb, make(map[int]models.User), conv, userId)
+
+		JSON(&models.ConversationResponse{
+			Code:   200,
+			Result: conv,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+const (
+	// maxGroupTitle caps the length of a group's title.
+	maxGroupTitle = 100
+	// maxGroupMembers caps how many users a group holds.
+	maxGroupMembers = 256
+)
+
+// groupMembers checks that everyone the user brings into a group is their
+// friend, and drops duplicates and the user themselves.
+func groupMembers(ctx context.Context, mongoDb *mongo.Database, userId int, ids []int) ([]int, bool, error) {
+	others := []int{}
+	for _, id := range ids {
+		if id != userId {
+			others = append(others, id)
+		}
+	}
+	return listMembers(ctx, mongoDb, userId, others)
+}
+
+// findGroup looks up a group the user is in, writing the error response if
+// there is none or if admin is set and the user isn't one of its admins.
+func findGroup(w http.ResponseWriter, r *http.Request, mongoDb *mongo.Database, id, userId int, admin bool) (*models.Conversation, bool) {
+	var conv models.Conversation
+	err := mongoDb.Collection("conversations").FindOne(r.Context(), bson.M{"_id": id, "group": true, "participant_ids": userId}).Decode(&conv)
+	if err == mongo.ErrNoDocuments {
+		w.WriteHeader(http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	if admin && groupRole(&conv, userId) != models.ConversationRoleAdmin {
+		w.WriteHeader(http.StatusForbidden)
+		return nil, false
+	}
+	return &conv, true
+}
+
+// groupRole returns the role of a member of the group.
+func groupRole(conv *models.Conversation, userId int) models.ConversationRole {
+	for _, m := range conv.Members {
+		if m.UserId == userId {
+			return m.Role
+		}
+	}
+	return models.ConversationRoleMember
+}
+
+// announceGroup has the chat hub post a system message about a change to a
+// group made by fromId, concerning targetId.
+func announceGroup(conversationId, fromId int, kind models.ChatKind, targetId int, content string) {
+	hb := actors.HubById("chat")
+	if hb == nil {
+		return
+	}
+	hb.Default(&models.ChatEvent{
+		Kind: models.ChatEventTypeSystem,
+		Chat: &models.Chat{
+			ConversationId: conversationId,
+			FromId:         fromId,
+			Kind:           kind,
+			TargetId:       targetId,
+			Content:        content,
+		},
+	}, nil)
+}
+
+// -- end --
@@ -0,0 +1,91 @@
+package operations
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// CreateFriendList
+func CreateFriendList(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "createFriendList"))
+	// -- init --
+	listId, _ := models.NewIDNode(19)
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		name := v.Form("name").String()
+
+		memberIds := v.Form("member_ids").Optional().IntArray()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("name", name))
+
+		name = strings.TrimSpace(name)
+		if name == "" || len(name) > maxFriendListName {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		members, ok, err := listMembers(r.Context(), mongoDb, userId, memberIds)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Lists can only hold friends",
+			}, w)
+			return
+		}
+
+		ct := time.Now()
+		list := &models.FriendList{
+			Id:        int(listId.Generate().Int64()),
+			UserId:    userId,
+			Name:      name,
+			MemberIds: members,
+			CreatedAt: ct,
+			UpdatedAt: ct,
+		}
+
+		_, err = mongoDb.Collection("friend_lists").InsertOne(r.Context(), list)
+		if models.IsDuplicateKeyError(err) {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "List already exists",
+			}, w)
+			return
+		}
+		if err != nil {
+			log.Error("Unable to create friend list", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		JSON(&models.FriendListResponse{
+			Code:   200,
+			Result: list,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
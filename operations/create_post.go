@@ -2,10 +2,12 @@ package operations
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"fr_book_api/models"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 	// -- imports --
@@ -31,6 +33,22 @@ func CreatePost(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.
 
 		image := v.Form("image").Optional().String()
 
+		pollOptions := v.Form("poll_options").Optional().StringArray()
+
+		pollMultiChoice := v.Form("poll_multi_choice").Optional().Bool()
+
+		pollHideResults := v.Form("poll_hide_results").Optional().Bool()
+
+		pollClosesAt := v.Form("poll_closes_at").Optional().DateTime()
+
+		status := v.Form("status").Optional().String()
+
+		publishAt := v.Form("publish_at").Optional().DateTime()
+
+		visibility := v.Form("visibility").Optional().String()
+
+		listId := v.Form("list_id").Optional().Int()
+
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
 		// -- code --
 		if !v.Valid() {
@@ -39,16 +57,58 @@ func CreatePost(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.
 		}
 		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("content", content), zap.Any("image", image))
 
+		ct := time.Now()
+		pStatus, pAt, ok := publishState(status, publishAt, ct)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		pVisibility, ok := parseVisibility(visibility)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if pVisibility == models.VisibilityList {
+			ok, err := ownsFriendList(r.Context(), mongoDb, userId, listId)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		} else {
+			listId = 0
+		}
+
 		post := &models.Post{
-			Content:   content,
-			Image:     image,
-			CreatedAt: time.Now(),
-			Title:     title,
-			UserId:    userId,
-			Video:     video,
-			Id:        int(postsId.Generate().Int64()),
+			Content:    content,
+			Image:      image,
+			CreatedAt:  ct,
+			Title:      title,
+			UserId:     userId,
+			Video:      video,
+			Status:     pStatus,
+			PublishAt:  pAt,
+			Visibility: pVisibility,
+			ListId:     listId,
+			Id:         int(postsId.Generate().Int64()),
+		}
+
+		if len(pollOptions) > 0 {
+			poll, ok := newPoll(pollOptions, pollMultiChoice, pollHideResults, pollClosesAt)
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			post.Kind = models.PostKindPoll
+			post.Poll = poll
 		}
 
+		post.Rerank(post.CreatedAt)
+
 		mongoDb.Collection("posts").InsertOne(r.Context(), post)
 
 		JSON(&models.StatusResponse{
@@ -59,4 +119,118 @@ func CreatePost(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.
 }
 
 // -- extra --
+
+const (
+	minPollOptions = 2
+	maxPollOptions = 10
+)
+
+// newPoll builds the poll for a post from the submitted option texts. Blank
+// options are dropped, and the poll is rejected if fewer than
+// minPollOptions or more than maxPollOptions remain, or if it would close
+// in the past.
+func newPoll(texts []string, multiChoice, hideResults bool, closesAt time.Time) (*models.Poll, bool) {
+	var options []*models.PollOption
+	for _, t := range texts {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		options = append(options, &models.PollOption{
+			Id:   len(options),
+			Text: t,
+		})
+	}
+	if len(options) < minPollOptions || len(options) > maxPollOptions {
+		return nil, false
+	}
+
+	poll := &models.Poll{
+		Options:     options,
+		MultiChoice: multiChoice,
+		HideResults: hideResults,
+	}
+	if !closesAt.IsZero() {
+		if !closesAt.After(time.Now()) {
+			return nil, false
+		}
+		poll.ClosesAt = &closesAt
+	}
+	return poll, true
+}
+
+// parseVisibility reads who a post is shown to. Posts are public unless
+// asked otherwise. Posts shown to a list also need the list's id.
+func parseVisibility(visibility string) (models.Visibility, bool) {
+	switch strings.ToLower(strings.TrimSpace(visibility)) {
+	case "", "public":
+		return models.VisibilityPublic, true
+	case "friends":
+		return models.VisibilityFriends, true
+	case "list":
+		return models.VisibilityList, true
+	}
+	return models.VisibilityPublic, false
+}
+
+// publishState works out the status of a new or edited post or article from
+// the submitted status and publish time. A publish time that has already
+// passed publishes the item straight away, while scheduling without one is
+// rejected.
+func publishState(status string, publishAt time.Time, ct time.Time) (models.PublishStatus, *time.Time, bool) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	switch status {
+	case "draft":
+		if publishAt.IsZero() {
+			return models.PublishStatusDraft, nil, true
+		}
+		return models.PublishStatusDraft, &publishAt, true
+	case "", "published", "scheduled":
+		if publishAt.IsZero() && status == "scheduled" {
+			return models.PublishStatusPublished, nil, false
+		}
+		if publishAt.IsZero() || !publishAt.After(ct) {
+			return models.PublishStatusPublished, nil, true
+		}
+		return models.PublishStatusScheduled, &publishAt, true
+	}
+	return models.PublishStatusPublished, nil, false
+}
+
+// applyPublishState adds the changes that move an item to the given status
+// to the $set and $unset parts of an update. Publishing an item bumps its
+// creation time so that it shows up at the top of the feed.
+func applyPublishState(set, unset bson.M, status models.PublishStatus, publishAt *time.Time, ct time.Time) {
+	if status == models.PublishStatusPublished {
+		set["created_at"] = ct
+		unset["status"] = ""
+		unset["publish_at"] = ""
+		return
+	}
+	set["status"] = status
+	if publishAt != nil {
+		set["publish_at"] = publishAt
+	} else {
+		unset["publish_at"] = ""
+	}
+}
+
+// unpublished lists the statuses of items that are hidden from everyone
+// but their author.
+var unpublished = []models.PublishStatus{
+	models.PublishStatusDraft,
+	models.PublishStatusScheduled,
+	models.PublishStatusReview,
+	models.PublishStatusArchived,
+}
+
+func isUnpublished(s models.PublishStatus) bool {
+	for _, u := range unpublished {
+		if s == u {
+			return true
+		}
+	}
+	return false
+}
+
 // -- end --
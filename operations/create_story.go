@@ -0,0 +1,102 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// CreateStory
+func CreateStory(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "createStory"))
+	// -- init --
+	storyId, _ := models.NewIDNode(13)
+	if mongoDb != nil {
+		// mongo drops stories and their views once they expire
+		for _, name := range []string{"stories", "story_views"} {
+			mongoDb.Collection(name).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+				Keys:    bson.M{"expires_at": 1},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			})
+		}
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		image := v.Form("image").Optional().String()
+
+		video := v.Form("video").Optional().String()
+
+		caption := v.Form("caption").Optional().String()
+
+		listId := v.Form("list_id").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("image", image), zap.Any("video", video))
+
+		if image == "" && video == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// stories go to all friends, or only to those on the given list
+		if listId != 0 {
+			ok, err := ownsFriendList(r.Context(), mongoDb, userId, listId)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+
+		ct := time.Now()
+		story := &models.Story{
+			Id:        int(storyId.Generate().Int64()),
+			UserId:    userId,
+			Image:     image,
+			Video:     video,
+			Caption:   caption,
+			ListId:    listId,
+			CreatedAt: ct,
+			ExpiresAt: ct.Add(storyLifetime),
+		}
+
+		if _, err := mongoDb.Collection("stories").InsertOne(r.Context(), story); err != nil {
+			log.Error("Unable to insert story", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// storyLifetime is how long a story stays visible after it is posted.
+const storyLifetime = 24 * time.Hour
+
+// -- end --
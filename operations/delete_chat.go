@@ -5,16 +5,15 @@ import (
 
 	"fr_book_api/models"
 
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 	// -- imports --
 	// -- end --
 )
 
-// UnlikePost
-func UnlikePost(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
-	oLog := logger.With(zap.String("op", "unlikePost"))
+// DeleteChat
+func DeleteChat(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "deleteChat"))
 	// -- init --
 	// -- end --
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -32,7 +31,13 @@ func UnlikePost(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.
 		}
 		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
 
-		mongoDb.Collection("posts").UpdateOne(r.Context(), bson.M{"_id": id}, bson.M{"$pull": bson.M{"likes": userId}})
+		// the message stays for everyone else
+		if _, ok := toChatHub(w, log, userId, &models.ChatEvent{
+			Kind:   models.ChatEventTypeDelete,
+			ChatId: id,
+		}); !ok {
+			return
+		}
 
 		JSON(&models.StatusResponse{
 			Code: 200,
@@ -0,0 +1,56 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// DeleteFriendList
+func DeleteFriendList(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "deleteFriendList"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		// posts and stories shared with the list are left visible to their
+		// author only
+		res, err := mongoDb.Collection("friend_lists").DeleteOne(r.Context(), bson.M{"_id": id, "user_id": userId})
+		if err != nil {
+			log.Error("Unable to delete friend list", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if res.DeletedCount == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -0,0 +1,159 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// FollowUser
+func FollowUser(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "followUser"))
+	// -- init --
+	followId, _ := models.NewIDNode(18)
+	if mongoDb != nil {
+		_, err := mongoDb.Collection("follows").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "from_id", Value: 1}, {Key: "to_id", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{Keys: bson.D{{Key: "to_id", Value: 1}, {Key: "pending", Value: 1}, {Key: "created_at", Value: -1}}},
+		})
+		if err != nil {
+			oLog.Error("Unable to create follows indexes", zap.Error(err))
+		}
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		if id == userId {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Can't follow yourself",
+			}, w)
+			return
+		}
+
+		var target models.User
+		if err := mongoDb.Collection("users").FindOne(r.Context(), bson.M{"_id": id}).Decode(&target); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		blocked, err := blockedBetween(r.Context(), mongoDb, userId, id)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if blocked {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// private accounts approve their followers
+		_, err = mongoDb.Collection("follows").InsertOne(r.Context(), &models.Follow{
+			Id:        int(followId.Generate().Int64()),
+			FromId:    userId,
+			ToId:      id,
+			Pending:   target.Private,
+			CreatedAt: time.Now(),
+		})
+		if models.IsDuplicateKeyError(err) {
+			JSON(&models.StatusResponse{
+				Code: 200,
+			}, w)
+			return
+		}
+		if err != nil {
+			log.Error("Unable to follow user", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if !target.Private {
+			countFollow(r.Context(), mongoDb, userId, id, 1)
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// followingIds returns the ids of everyone the user follows, leaving out
+// follows still waiting for approval.
+func followingIds(ctx context.Context, mongoDb *mongo.Database, userId int) ([]int, error) {
+	c, err := mongoDb.Collection("follows").Find(ctx, bson.M{
+		"from_id": userId,
+		"pending": bson.M{"$ne": true},
+	}, options.Find().SetProjection(bson.M{"to_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Close(ctx)
+
+	var ids []int
+	for c.Next(ctx) {
+		var f models.Follow
+		if err := c.Decode(&f); err != nil {
+			continue
+		}
+		ids = append(ids, f.ToId)
+	}
+	return ids, nil
+}
+
+// countFollow keeps the follower and following counts of both users in
+// step with an approved follow being added or removed.
+func countFollow(ctx context.Context, mongoDb *mongo.Database, fromId, toId, n int) {
+	mongoDb.Collection("users").UpdateOne(ctx, bson.M{"_id": fromId}, bson.M{"$inc": bson.M{"following_count": n}})
+	mongoDb.Collection("users").UpdateOne(ctx, bson.M{"_id": toId}, bson.M{"$inc": bson.M{"followers_count": n}})
+}
+
+// dropFollow removes the follow of fromId on toId, if any.
+func dropFollow(ctx context.Context, mongoDb *mongo.Database, fromId, toId int) error {
+	var f models.Follow
+	err := mongoDb.Collection("follows").FindOneAndDelete(ctx, bson.M{"from_id": fromId, "to_id": toId}).Decode(&f)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !f.Pending {
+		countFollow(ctx, mongoDb, fromId, toId, -1)
+	}
+	return nil
+}
+
+// -- end --
@@ -1,12 +1,15 @@
 package operations
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"fr_book_api/models"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 	// -- imports --
 	// -- end --
@@ -16,12 +19,29 @@ import (
 func GetArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
 	oLog := logger.With(zap.String("op", "getArticle"))
 	// -- init --
+	if mongoDb != nil {
+		// one view per reader and window, and one reader entry per article
+		mongoDb.Collection("article_views").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "article_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "window", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		mongoDb.Collection("article_readers").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "article_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		mongoDb.Collection("article_daily_stats").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "article_id", Value: 1}, {Key: "day", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+	}
 	// -- end --
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		v := models.NewValidator(r).Secret(sugar)
 
 		userId := v.Token("user_id").Int()
 
+		userType := v.Token("user_type").Optional().UserType()
+
 		id := v.Path("id").Int()
 
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
@@ -41,6 +61,24 @@ func GetArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.
 			return
 		}
 
+		if !canSeeArticle(&article, userId, userType == models.UserTypeAdmin) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// authors looking at their own articles are not readers
+		if article.UserId != userId && !isUnpublished(article.Status) {
+			if err := recordArticleView(r.Context(), mongoDb, &article, userId, time.Now()); err != nil {
+				log.Error("Unable to record view", zap.Error(err))
+			}
+		}
+
+		articleReactions(&article, userId)
+
+		// content is stored as Markdown, clients get it rendered
+		article.ContentHtml, article.Toc = models.RenderMarkdown(article.Content)
+		article.ReadingTime = models.ReadingTime(article.ContentHtml)
+
 		JSON(&models.ArticleResponse{
 			Code:   200,
 			Result: &article,
@@ -50,4 +88,76 @@ func GetArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.
 }
 
 // -- extra --
+
+var articleViewId, _ = models.NewIDNode(16)
+
+// viewWindow is how long repeated views by the same reader count as one.
+const viewWindow = 30 * time.Minute
+
+// statsDay is the day a statistic is counted for.
+func statsDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// countArticleStat adds to an article's counter and to the same counter
+// of the day.
+func countArticleStat(ctx context.Context, db *mongo.Database, articleId int, counter, daily string, ct time.Time) error {
+	if _, err := db.Collection("articles").UpdateOne(ctx, bson.M{"_id": articleId}, bson.M{"$inc": bson.M{counter: 1}}); err != nil {
+		return err
+	}
+	_, err := db.Collection("article_daily_stats").UpdateOne(ctx, bson.M{
+		"article_id": articleId,
+		"day":        statsDay(ct),
+	}, bson.M{"$inc": bson.M{daily: 1}}, options.Update().SetUpsert(true))
+	return err
+}
+
+// recordArticleView counts a view of the article, unless the user viewed
+// it within the current window, and counts new readers.
+func recordArticleView(ctx context.Context, db *mongo.Database, article *models.Article, userId int, ct time.Time) error {
+	res, err := db.Collection("article_views").UpdateOne(ctx, bson.M{
+		"article_id": article.Id,
+		"user_id":    userId,
+		"window":     ct.Truncate(viewWindow),
+	}, bson.M{"$setOnInsert": &models.ArticleView{
+		Id:        int(articleViewId.Generate().Int64()),
+		ArticleId: article.Id,
+		UserId:    userId,
+		Window:    ct.Truncate(viewWindow),
+		CreatedAt: ct,
+	}}, options.Update().SetUpsert(true))
+	if err != nil {
+		if models.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	}
+	if res.UpsertedCount == 0 {
+		return nil
+	}
+	if err := countArticleStat(ctx, db, article.Id, "views_count", "views", ct); err != nil {
+		return err
+	}
+
+	res, err = db.Collection("article_readers").UpdateOne(ctx, bson.M{
+		"article_id": article.Id,
+		"user_id":    userId,
+	}, bson.M{"$setOnInsert": &models.ArticleReader{
+		Id:        int(articleViewId.Generate().Int64()),
+		ArticleId: article.Id,
+		UserId:    userId,
+		CreatedAt: ct,
+	}}, options.Update().SetUpsert(true))
+	if err != nil {
+		if models.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	}
+	if res.UpsertedCount == 0 {
+		return nil
+	}
+	return countArticleStat(ctx, db, article.Id, "readers_count", "readers", ct)
+}
+
 // -- end --
@@ -0,0 +1,244 @@
+package operations
+
+import (
+	"net/http"
+	"strings"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetArticleDiff
+func GetArticleDiff(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getArticleDiff"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		userType := v.Token("user_type").Optional().UserType()
+
+		id := v.Path("id").Int()
+
+		from := v.Query("from").Int()
+
+		to := v.Query("to").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("from", from), zap.Any("to", to))
+
+		var article models.Article
+		if err := mongoDb.Collection("articles").FindOne(r.Context(), bson.M{"_id": id}).Decode(&article); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		isAdmin := userType == models.UserTypeAdmin
+		if !canSeeArticle(&article, userId, isAdmin) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if article.UserId != userId && !isAdmin {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		var revs [2]models.ArticleRevision
+		for i, n := range []int{from, to} {
+			if err := mongoDb.Collection("article_revisions").FindOne(r.Context(), bson.M{"article_id": id, "revision": n}).Decode(&revs[i]); err != nil {
+				if err == mongo.ErrNoDocuments {
+					w.WriteHeader(http.StatusNotFound)
+				} else {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				return
+			}
+		}
+
+		diff := &models.ArticleDiff{
+			From:   from,
+			To:     to,
+			Fields: []*models.FieldDiff{},
+		}
+		for _, f := range revisionFields(&revs[0], &revs[1]) {
+			if f.old != f.new {
+				diff.Fields = append(diff.Fields, &models.FieldDiff{
+					Field: f.name,
+					Diff:  lineDiff(f.old, f.new),
+				})
+			}
+		}
+
+		JSON(&models.ArticleDiffResponse{
+			Code:   200,
+			Result: diff,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+type revisionField struct {
+	name string
+	old  string
+	new  string
+}
+
+// revisionFields pairs up the content of two revisions field by field.
+// Tags are compared one per line.
+func revisionFields(from, to *models.ArticleRevision) []revisionField {
+	return []revisionField{
+		{"title", from.Title, to.Title},
+		{"author_name", from.AuthorName, to.AuthorName},
+		{"description", from.Description, to.Description},
+		{"tags", strings.Join(from.Tags, "\n"), strings.Join(to.Tags, "\n")},
+		{"content", from.Content, to.Content},
+		{"photo", from.Photo, to.Photo},
+		{"pdf", from.Pdf, to.Pdf},
+	}
+}
+
+// maxDiffEdits caps how many lines a diff may add and remove before it
+// stops looking for the shortest one, which takes time and memory growing
+// with its square. Past it, the changed lines are shown replaced as a whole.
+const maxDiffEdits = 1000
+
+// lineDiff renders a line based diff of a and b. Removed lines start with
+// "-", added ones with "+" and unchanged ones with a space.
+func lineDiff(a, b string) string {
+	x, y := splitLines(a), splitLines(b)
+
+	// common leading and trailing lines need no comparing
+	head := 0
+	for head < len(x) && head < len(y) && x[head] == y[head] {
+		head++
+	}
+	tail := 0
+	for tail < len(x)-head && tail < len(y)-head && x[len(x)-1-tail] == y[len(y)-1-tail] {
+		tail++
+	}
+	xs, ys := x[head:len(x)-tail], y[head:len(y)-tail]
+
+	var sb strings.Builder
+	for _, l := range x[:head] {
+		sb.WriteString(" " + l + "\n")
+	}
+	if edits, ok := shortestEdit(xs, ys); ok {
+		for _, e := range edits {
+			sb.WriteString(e)
+			sb.WriteString("\n")
+		}
+	} else {
+		for _, l := range xs {
+			sb.WriteString("-" + l + "\n")
+		}
+		for _, l := range ys {
+			sb.WriteString("+" + l + "\n")
+		}
+	}
+	for _, l := range x[len(x)-tail:] {
+		sb.WriteString(" " + l + "\n")
+	}
+	return sb.String()
+}
+
+// shortestEdit finds the shortest way of turning xs into ys with Myers'
+// algorithm, and returns its lines prefixed the way lineDiff renders them.
+// It gives up once more than maxDiffEdits lines would change.
+func shortestEdit(xs, ys []string) ([]string, bool) {
+	n, m := len(xs), len(ys)
+	limit := n + m
+	if limit > maxDiffEdits {
+		limit = maxDiffEdits
+	}
+
+	// v[off+k] is how far along xs the furthest path on diagonal k got;
+	// trace[d] keeps diagonals -d..d of v as they were before step d
+	off := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+	end := -1
+	for d := 0; d <= limit && end < 0; d++ {
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			x := v[off+k-1] + 1
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			}
+			y := x - k
+			for x < n && y < m && xs[x] == ys[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				end = d
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return nil, false
+	}
+
+	// walk back from the end, collecting the edits in reverse
+	var edits []string
+	x, y := n, m
+	for d := end; d > 0; d-- {
+		prev := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && prev[k-1+d] < prev[k+1+d]) {
+			prevK = k + 1
+		}
+		prevX := prev[prevK+d]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, " "+xs[x-1])
+			x--
+			y--
+		}
+		if x == prevX {
+			edits = append(edits, "+"+ys[prevY])
+		} else {
+			edits = append(edits, "-"+xs[prevX])
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		edits = append(edits, " "+xs[x-1])
+		x--
+		y--
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits, true
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+// -- end --
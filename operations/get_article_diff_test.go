@@ -0,0 +1,112 @@
+package operations
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"same", "a\nb", "a\nb", " a\n b\n"},
+		{"empty", "", "", ""},
+		{"from nothing", "", "a\nb", "+a\n+b\n"},
+		{"to nothing", "a\nb", "", "-a\n-b\n"},
+		{"insert", "a\nc", "a\nb\nc", " a\n+b\n c\n"},
+		{"insert at start", "b\nc", "a\nb\nc", "+a\n b\n c\n"},
+		{"insert at end", "a\nb", "a\nb\nc", " a\n b\n+c\n"},
+		{"delete", "a\nb\nc", "a\nc", " a\n-b\n c\n"},
+		{"delete several", "a\nb\nc\nd\ne", "a\ne", " a\n-b\n-c\n-d\n e\n"},
+		{"replace", "a\nb\nc", "a\nx\nc", " a\n-b\n+x\n c\n"},
+		{"replace all", "a\nb", "x\ny", "-a\n-b\n+x\n+y\n"},
+		{"move", "a\nb\nc\nd", "b\nc\na\nd", "-a\n b\n c\n+a\n d\n"},
+		{"interleaved", "a\nb\nc\nd\ne", "a\nx\nc\ne\ny", " a\n-b\n+x\n c\n-d\n e\n+y\n"},
+		{"line endings", "a\r\nb", "a\nb", " a\n b\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineDiff(tt.a, tt.b); got != tt.want {
+				t.Errorf("lineDiff(%q, %q)\n got: %q\nwant: %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortestEdit(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []string
+		want   []string
+	}{
+		{"nothing", nil, nil, nil},
+		{"insert only", nil, []string{"a", "b"}, []string{"+a", "+b"}},
+		{"delete only", []string{"a", "b"}, nil, []string{"-a", "-b"}},
+		{"replace", []string{"a"}, []string{"b"}, []string{"-a", "+b"}},
+		{"keep", []string{"a", "b", "c"}, []string{"a", "x", "c"}, []string{" a", "-b", "+x", " c"}},
+		{"shortest", []string{"a", "b", "c", "a", "b", "b", "a"}, []string{"c", "b", "a", "b", "a", "c"},
+			[]string{"-a", "-b", " c", "+b", " a", " b", "-b", " a", "+c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := shortestEdit(tt.xs, tt.ys)
+			if !ok {
+				t.Fatalf("shortestEdit(%q, %q) gave up", tt.xs, tt.ys)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("shortestEdit(%q, %q)\n got: %q\nwant: %q", tt.xs, tt.ys, got, tt.want)
+			}
+		})
+	}
+}
+
+// numbered returns n distinct lines starting with prefix.
+func numbered(prefix string, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return lines
+}
+
+func TestShortestEditLimit(t *testing.T) {
+	// as many changes as allowed still get the shortest edit
+	xs, ys := numbered("a", maxDiffEdits/2), numbered("b", maxDiffEdits/2)
+	if edits, ok := shortestEdit(xs, ys); !ok || len(edits) != maxDiffEdits {
+		t.Errorf("shortestEdit with %d changes = %d edits, %v; want %d, true", maxDiffEdits, len(edits), ok, maxDiffEdits)
+	}
+
+	// one more and it gives up
+	ys = append(ys, "b")
+	if _, ok := shortestEdit(xs, ys); ok {
+		t.Errorf("shortestEdit with %d changes didn't give up", maxDiffEdits+1)
+	}
+}
+
+func TestLineDiffFallback(t *testing.T) {
+	// past the limit, the changed lines are replaced as a whole, between
+	// the common lines around them
+	xs, ys := numbered("a", maxDiffEdits), numbered("b", maxDiffEdits)
+	a := "top\n" + strings.Join(xs, "\n") + "\nbottom"
+	b := "top\n" + strings.Join(ys, "\n") + "\nbottom"
+
+	var want strings.Builder
+	want.WriteString(" top\n")
+	for _, l := range xs {
+		want.WriteString("-" + l + "\n")
+	}
+	for _, l := range ys {
+		want.WriteString("+" + l + "\n")
+	}
+	want.WriteString(" bottom\n")
+
+	if got := lineDiff(a, b); got != want.String() {
+		t.Errorf("lineDiff past the limit = %q..., want %q...", got[:40], want.String()[:40])
+	}
+}
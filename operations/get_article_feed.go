@@ -0,0 +1,458 @@
+package operations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetArticleFeed
+func GetArticleFeed(mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getArticleFeed"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r)
+
+		format := v.Path("format").String()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("format", format))
+
+		writeArticleFeed(w, r, mongoDb, log, &articleFeed{
+			format: format,
+			title:  "frBook articles",
+			path:   "/articles/feed",
+			filter: bson.M{},
+		})
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// feedSize is how many of the latest articles a feed lists.
+const feedSize = 50
+
+// articleFeed describes one feed of published articles.
+type articleFeed struct {
+	format string
+	title  string
+	// path is where the feed is served, without the format extension.
+	path   string
+	filter bson.M
+}
+
+// writeArticleFeed renders the latest articles matching the feed's filter
+// as Atom, RSS or JSON Feed. Readers polling the feed get a 304 as long as
+// nothing changed.
+func writeArticleFeed(w http.ResponseWriter, r *http.Request, mongoDb *mongo.Database, log *zap.Logger, f *articleFeed) {
+	if f.format != "atom" && f.format != "rss" && f.format != "json" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	filter := bson.M{"status": bson.M{"$nin": unpublished}}
+	for key, val := range f.filter {
+		filter[key] = val
+	}
+
+	// only what the feed shows is loaded, newest first
+	opts := options.Find().
+		SetSort(bson.D{{Key: "published_at", Value: -1}, {Key: "created_at", Value: -1}}).
+		SetLimit(feedSize).
+		SetProjection(bson.M{
+			"title": 1, "description": 1, "content": 1, "author_name": 1, "user_id": 1,
+			"published_at": 1, "created_at": 1, "tags": 1, "photo": 1, "revision": 1,
+		})
+	c, err := mongoDb.Collection("articles").Find(r.Context(), filter, opts)
+	if err != nil {
+		log.Error("Unable to get articles", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var articles []*models.Article
+	for c.Next(r.Context()) {
+		var article models.Article
+		if err := c.Decode(&article); err != nil {
+			log.Error("Unable to decode article", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			c.Close(r.Context())
+			return
+		}
+		articles = append(articles, &article)
+	}
+	c.Close(r.Context())
+
+	updated := time.Unix(models.Epoch, 0)
+	if len(articles) > 0 && !articleTime(articles[0]).IsZero() {
+		updated = articleTime(articles[0])
+	}
+
+	// edits don't change publishing times, so the ETag is what tells
+	// readers about them: it covers every listed article's revision and is
+	// known before anything is rendered
+	hash := sha256.New()
+	hash.Write([]byte(f.format + "\n" + f.title))
+	for _, a := range articles {
+		hash.Write([]byte("\n" + strconv.Itoa(a.Id) + ":" + strconv.Itoa(a.Revision) + ":" + strconv.FormatInt(articleTime(a).UnixNano(), 10)))
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	lastModified := updated.UTC().Format(http.TimeFormat)
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", lastModified)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		if match == etag || match == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	} else if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !updated.Truncate(time.Second).After(since) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	base := feedBase(r)
+	users := make(map[int]models.User)
+	var items []*feedItem
+	for _, a := range articles {
+		items = append(items, newFeedItem(r.Context(), mongoDb, users, base, a))
+	}
+
+	var body []byte
+	var contentType string
+	switch f.format {
+	case "atom":
+		body, err = atomFeed(f, base, updated, items)
+		contentType = "application/atom+xml; charset=utf-8"
+	case "rss":
+		body, err = rssFeed(f, base, updated, items)
+		contentType = "application/rss+xml; charset=utf-8"
+	case "json":
+		body, err = jsonFeed(f, base, items)
+		contentType = "application/feed+json; charset=utf-8"
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Error("Unable to render feed", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// feedBase is the public address of the API, as seen by the client.
+func feedBase(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if p := r.Header.Get("X-Forwarded-Proto"); p == "http" || p == "https" {
+		scheme = p
+	}
+	return scheme + "://" + r.Host
+}
+
+// feedItem is an article in the form all feed formats are built from.
+type feedItem struct {
+	id          string
+	link        string
+	title       string
+	summary     string
+	contentHtml string
+	author      string
+	published   time.Time
+	tags        []string
+	image       string
+	imageType   string
+}
+
+func newFeedItem(ctx context.Context, mongoDb *mongo.Database, users map[int]models.User, base string, a *models.Article) *feedItem {
+	contentHtml, _ := models.RenderMarkdown(a.Content)
+	item := &feedItem{
+		id:          base + "/articles/" + strconv.Itoa(a.Id),
+		link:        base + "/articles/" + strconv.Itoa(a.Id),
+		title:       a.Title,
+		summary:     a.Description,
+		contentHtml: contentHtml,
+		author:      a.AuthorName,
+		published:   articleTime(a),
+		tags:        a.Tags,
+	}
+
+	if item.author == "" {
+		if u, ok := lookupUser(ctx, mongoDb, users, a.UserId); ok {
+			item.author = u.Name
+		}
+	}
+
+	if a.Photo != "" {
+		item.image = a.Photo
+		if u, err := url.Parse(a.Photo); err != nil || u.Scheme == "" {
+			item.image = base + "/assets/" + url.PathEscape(path.Base(a.Photo))
+		}
+		item.imageType = mime.TypeByExtension(path.Ext(a.Photo))
+		if item.imageType == "" {
+			item.imageType = "application/octet-stream"
+		}
+	}
+	return item
+}
+
+type atomLink struct {
+	Rel    string `xml:"rel,attr,omitempty"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Id         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomDoc struct {
+	XMLName xml.Name     `xml:"feed"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Id      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Links   []atomLink   `xml:"link"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+func atomFeed(f *articleFeed, base string, updated time.Time, items []*feedItem) ([]byte, error) {
+	doc := &atomDoc{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		Id:      base + f.path + ".atom",
+		Title:   f.title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links:   []atomLink{{Rel: "self", Href: base + f.path + ".atom", Type: "application/atom+xml"}},
+	}
+
+	for _, it := range items {
+		e := &atomEntry{
+			Id:        it.id,
+			Title:     it.title,
+			Updated:   it.published.UTC().Format(time.RFC3339),
+			Published: it.published.UTC().Format(time.RFC3339),
+			Links:     []atomLink{{Rel: "alternate", Href: it.link}},
+		}
+		if it.author != "" {
+			e.Author = &atomAuthor{Name: it.author}
+		}
+		if it.summary != "" {
+			e.Summary = &atomText{Type: "text", Body: it.summary}
+		}
+		if it.contentHtml != "" {
+			e.Content = &atomText{Type: "html", Body: it.contentHtml}
+		}
+		for _, t := range it.tags {
+			e.Categories = append(e.Categories, atomCategory{Term: t})
+		}
+		if it.image != "" {
+			e.Links = append(e.Links, atomLink{Rel: "enclosure", Href: it.image, Type: it.imageType})
+		}
+		doc.Entries = append(doc.Entries, e)
+	}
+
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+type rssEnclosure struct {
+	Url    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type rssGuid struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Body        string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Guid        rssGuid       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Creator     string        `xml:"dc:creator,omitempty"`
+	Description string        `xml:"description,omitempty"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate"`
+	AtomLink      atomLink   `xml:"atom:link"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Dc      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+func rssFeed(f *articleFeed, base string, updated time.Time, items []*feedItem) ([]byte, error) {
+	doc := &rssDoc{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Dc:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         f.title,
+			Link:          base + f.path + ".rss",
+			Description:   f.title,
+			LastBuildDate: updated.UTC().Format(time.RFC1123Z),
+			AtomLink:      atomLink{Rel: "self", Href: base + f.path + ".rss", Type: "application/rss+xml"},
+		},
+	}
+
+	for _, it := range items {
+		item := &rssItem{
+			Title:       it.title,
+			Link:        it.link,
+			Guid:        rssGuid{IsPermaLink: "false", Body: it.id},
+			PubDate:     it.published.UTC().Format(time.RFC1123Z),
+			Creator:     it.author,
+			Description: it.summary,
+			Categories:  it.tags,
+		}
+		if item.Description == "" {
+			item.Description = it.contentHtml
+		}
+		if it.image != "" {
+			// the size of remote images is unknown, which RSS readers accept
+			item.Enclosure = &rssEnclosure{Url: it.image, Type: it.imageType, Length: "0"}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedAttachment struct {
+	Url      string `json:"url"`
+	MimeType string `json:"mime_type"`
+}
+
+type jsonFeedItem struct {
+	Id            string               `json:"id"`
+	Url           string               `json:"url"`
+	Title         string               `json:"title,omitempty"`
+	ContentHtml   string               `json:"content_html"`
+	Summary       string               `json:"summary,omitempty"`
+	Image         string               `json:"image,omitempty"`
+	DatePublished string               `json:"date_published"`
+	Authors       []jsonFeedAuthor     `json:"authors,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+}
+
+type jsonFeedDoc struct {
+	Version     string          `json:"version"`
+	Title       string          `json:"title"`
+	HomePageUrl string          `json:"home_page_url"`
+	FeedUrl     string          `json:"feed_url"`
+	Items       []*jsonFeedItem `json:"items"`
+}
+
+func jsonFeed(f *articleFeed, base string, items []*feedItem) ([]byte, error) {
+	doc := &jsonFeedDoc{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.title,
+		HomePageUrl: base + "/articles",
+		FeedUrl:     base + f.path + ".json",
+		Items:       []*jsonFeedItem{},
+	}
+
+	for _, it := range items {
+		item := &jsonFeedItem{
+			Id:            it.id,
+			Url:           it.link,
+			Title:         it.title,
+			ContentHtml:   it.contentHtml,
+			Summary:       it.summary,
+			Image:         it.image,
+			DatePublished: it.published.UTC().Format(time.RFC3339),
+			Tags:          it.tags,
+		}
+		if it.author != "" {
+			item.Authors = []jsonFeedAuthor{{Name: it.author}}
+		}
+		if it.image != "" {
+			item.Attachments = []jsonFeedAttachment{{Url: it.image, MimeType: it.imageType}}
+		}
+		doc.Items = append(doc.Items, item)
+	}
+
+	return json.Marshal(doc)
+}
+
+// -- end --
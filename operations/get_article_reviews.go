@@ -0,0 +1,72 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetArticleReviews
+func GetArticleReviews(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getArticleReviews"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		userType := v.Token("user_type").Optional().UserType()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("user_type", userType))
+
+		if userType != models.UserTypeAdmin {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		c, err := mongoDb.Collection("articles").Find(r.Context(), bson.M{"status": models.PublishStatusReview}, options.Find().SetSort(bson.M{"created_at": 1}))
+		if err != nil {
+			log.Error("Unable to get articles", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		var articles []*models.Article
+
+		for c.Next(r.Context()) {
+			var article models.Article
+			if err := c.Decode(&article); err != nil {
+				log.Error("Unable to decode article", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			articleReactions(&article, userId)
+			articles = append(articles, &article)
+		}
+
+		JSON(&models.ArticleListResponse{
+			Code:   200,
+			Result: articles,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
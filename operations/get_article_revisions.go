@@ -0,0 +1,200 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetArticleRevisions
+func GetArticleRevisions(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getArticleRevisions"))
+	// -- init --
+	if mongoDb != nil {
+		// revision numbers are unique per article, so concurrent edits of
+		// the same revision can not both be stored
+		mongoDb.Collection("article_revisions").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "article_id", Value: 1}, {Key: "revision", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		userType := v.Token("user_type").Optional().UserType()
+
+		id := v.Path("id").Int()
+
+		start := v.Query("start").Optional().Int()
+
+		limit := v.Query("limit").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		var article models.Article
+		if err := mongoDb.Collection("articles").FindOne(r.Context(), bson.M{"_id": id}).Decode(&article); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		isAdmin := userType == models.UserTypeAdmin
+		if !canSeeArticle(&article, userId, isAdmin) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if article.UserId != userId && !isAdmin {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		start, limit = pageBounds(start, limit)
+
+		filter := bson.M{"article_id": id}
+		total, err := mongoDb.Collection("article_revisions").CountDocuments(r.Context(), filter)
+		if err != nil {
+			log.Error("Unable to count revisions", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		c, err := mongoDb.Collection("article_revisions").Find(r.Context(), filter, options.Find().SetSort(bson.M{"revision": -1}).SetSkip(int64(start)).SetLimit(int64(limit)))
+		if err != nil {
+			log.Error("Unable to get revisions", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		var revisions []*models.ArticleRevision
+
+		for c.Next(r.Context()) {
+			var rev models.ArticleRevision
+			if err := c.Decode(&rev); err != nil {
+				log.Error("Unable to decode revision", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			revisions = append(revisions, &rev)
+		}
+
+		JSON(&models.ArticleRevisionListResponse{
+			Code:   200,
+			Result: revisions,
+			Start:  start,
+			Total:  int(total),
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+var revisionId, _ = models.NewIDNode(15)
+
+// articleRevision captures the editable content of an article.
+func articleRevision(a *models.Article) *models.ArticleRevision {
+	return &models.ArticleRevision{
+		ArticleId:   a.Id,
+		AuthorName:  a.AuthorName,
+		Title:       a.Title,
+		Description: a.Description,
+		Tags:        a.Tags,
+		Content:     a.Content,
+		Photo:       a.Photo,
+		Pdf:         a.Pdf,
+	}
+}
+
+// revisionFilter matches articles at the given revision. Articles written
+// before revisions existed carry no revision at all.
+func revisionFilter(n int) interface{} {
+	if n == 0 {
+		return bson.M{"$exists": false}
+	}
+	return n
+}
+
+// reviseArticle stores rev as the next revision of the article and applies
+// set and unset to it. It reports false when the article changed since it
+// was read, in which case nothing is stored.
+func reviseArticle(ctx context.Context, db *mongo.Database, article *models.Article, rev *models.ArticleRevision, set, unset bson.M) (bool, error) {
+	revisions := db.Collection("article_revisions")
+
+	base := article.Revision
+	if base == 0 {
+		// older articles get their current state recorded as the first
+		// revision, so the edit can be undone
+		first := articleRevision(article)
+		first.Id = int(revisionId.Generate().Int64())
+		first.Revision = 1
+		first.UserId = article.UserId
+		first.CreatedAt = articleTime(article)
+		if _, err := revisions.InsertOne(ctx, first); err != nil && !models.IsDuplicateKeyError(err) {
+			return false, err
+		}
+		base = 1
+	}
+
+	rev.Id = int(revisionId.Generate().Int64())
+	rev.ArticleId = article.Id
+	rev.Revision = base + 1
+	if _, err := revisions.InsertOne(ctx, rev); err != nil {
+		if models.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	set["revision"] = rev.Revision
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	res, err := db.Collection("articles").UpdateOne(ctx, bson.M{
+		"_id":      article.Id,
+		"status":   statusFilter(article.Status),
+		"revision": revisionFilter(article.Revision),
+	}, update)
+	if err != nil || res.MatchedCount == 0 {
+		revisions.DeleteOne(ctx, bson.M{"_id": rev.Id})
+		return false, err
+	}
+	return true, nil
+}
+
+// firstRevision records a new article as its first revision.
+func firstRevision(ctx context.Context, db *mongo.Database, article *models.Article, ct time.Time) error {
+	rev := articleRevision(article)
+	rev.Id = int(revisionId.Generate().Int64())
+	rev.Revision = 1
+	rev.UserId = article.UserId
+	rev.CreatedAt = ct
+	_, err := db.Collection("article_revisions").InsertOne(ctx, rev)
+	return err
+}
+
+// -- end --
@@ -0,0 +1,145 @@
+package operations
+
+import (
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetArticleStats
+func GetArticleStats(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getArticleStats"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		userType := v.Token("user_type").Optional().UserType()
+
+		id := v.Path("id").Int()
+
+		days := v.Query("days").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("days", days))
+
+		var article models.Article
+		if err := mongoDb.Collection("articles").FindOne(r.Context(), bson.M{"_id": id}).Decode(&article); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		// stats are for the author's eyes
+		if article.UserId != userId && userType != models.UserTypeAdmin {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if days <= 0 {
+			days = defaultStatsDays
+		}
+		if days > maxStatsDays {
+			days = maxStatsDays
+		}
+
+		// one entry per day, oldest first, including the days without any
+		// activity
+		ct := time.Now()
+		byDay := make(map[string]*models.ArticleDayStats)
+		series := make([]*models.ArticleDayStats, days)
+		for i := range series {
+			day := statsDay(ct.AddDate(0, 0, i-days+1))
+			series[i] = &models.ArticleDayStats{Day: day}
+			byDay[day] = series[i]
+		}
+
+		c, err := mongoDb.Collection("article_daily_stats").Find(r.Context(), bson.M{
+			"article_id": id,
+			"day":        bson.M{"$gte": series[0].Day},
+		})
+		if err != nil {
+			log.Error("Unable to get daily stats", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		for c.Next(r.Context()) {
+			var d models.ArticleDayStats
+			if err := c.Decode(&d); err != nil {
+				log.Error("Unable to decode daily stats", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if s, ok := byDay[d.Day]; ok {
+				*s = d
+			}
+		}
+
+		stats := &models.ArticleStats{
+			ArticleId:        id,
+			ViewsCount:       article.ViewsCount,
+			ReadersCount:     article.ReadersCount,
+			CompletionsCount: article.CompletionsCount,
+			Days:             series,
+		}
+		if article.ReadersCount > 0 {
+			stats.CompletionRate = float64(article.CompletionsCount) / float64(article.ReadersCount)
+		}
+
+		p, err := mongoDb.Collection("article_readers").Aggregate(r.Context(), mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"article_id": id}}},
+			{{Key: "$group", Value: bson.M{"_id": nil, "progress": bson.M{"$avg": bson.M{"$ifNull": bson.A{"$progress", 0}}}}}},
+		})
+		if err != nil {
+			log.Error("Unable to get reading progress", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer p.Close(r.Context())
+
+		if p.Next(r.Context()) {
+			var avg struct {
+				Progress float64 `bson:"progress"`
+			}
+			if err := p.Decode(&avg); err == nil {
+				stats.AverageProgress = avg.Progress
+			}
+		}
+
+		JSON(&models.ArticleStatsResponse{
+			Code:   200,
+			Result: stats,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+const (
+	defaultStatsDays = 30
+	maxStatsDays     = 365
+)
+
+// -- end --
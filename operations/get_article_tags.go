@@ -0,0 +1,98 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetArticleTags
+func GetArticleTags(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getArticleTags"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		start := v.Query("start").Optional().Int()
+
+		limit := v.Query("limit").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId))
+
+		start, limit = pageBounds(start, limit)
+
+		c, err := mongoDb.Collection("articles").Aggregate(r.Context(), mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"status": bson.M{"$nin": unpublished}}}},
+			{{Key: "$unwind", Value: "$tags"}},
+			{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+			{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+			{{Key: "$facet", Value: bson.M{
+				"total": bson.A{bson.M{"$count": "n"}},
+				"tags":  bson.A{bson.M{"$skip": start}, bson.M{"$limit": limit}},
+			}}},
+		})
+		if err != nil {
+			log.Error("Unable to count tags", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		var page struct {
+			Total []struct {
+				N int `bson:"n"`
+			} `bson:"total"`
+			Tags []struct {
+				Name  string `bson:"_id"`
+				Count int    `bson:"count"`
+			} `bson:"tags"`
+		}
+		if c.Next(r.Context()) {
+			if err := c.Decode(&page); err != nil {
+				log.Error("Unable to decode tags", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		var tags []*models.TagCount
+		for _, t := range page.Tags {
+			tags = append(tags, &models.TagCount{
+				Name:  t.Name,
+				Count: t.Count,
+			})
+		}
+
+		total := 0
+		if len(page.Total) > 0 {
+			total = page.Total[0].N
+		}
+
+		JSON(&models.TagCountListResponse{
+			Code:   200,
+			Result: tags,
+			Start:  start,
+			Total:  total,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"net/http"
 	"sort"
 
@@ -17,24 +18,48 @@ import (
 func GetArticles(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
 	oLog := logger.With(zap.String("op", "getArticles"))
 	// -- init --
+	if mongoDb != nil {
+		mongoDb.Collection("articles").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys: bson.D{{Key: "tags", Value: 1}},
+		})
+	}
 	// -- end --
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		v := models.NewValidator(r).Secret(sugar)
 
 		userId := v.Token("user_id").Int()
 
+		tag := v.Query("tag").Optional().String()
+
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
 		// -- code --
 		if !v.Valid() {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		log.Debug("Start Operation", zap.Any("user_id", userId))
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("tag", tag))
 
 		var articles []*models.Article
 		users := make(map[int]models.User)
 
-		c, err := mongoDb.Collection("articles").Find(r.Context(), bson.M{})
+		saved, err := savedTargets(r.Context(), mongoDb, userId, models.TargetTypeArticle)
+		if err != nil {
+			log.Error("Unable to get bookmarks", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		filter := bson.M{"status": bson.M{"$nin": unpublished}}
+		if v.HasQuery("tag") {
+			tags := models.NormalizeTags(tag)
+			if len(tags) != 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			filter["tags"] = tags[0]
+		}
+
+		c, err := mongoDb.Collection("articles").Find(r.Context(), filter)
 		if err != nil {
 			log.Error("Unable to get articles", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
@@ -58,12 +83,14 @@ func GetArticles(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http
 				users[article.UserId] = u
 			}
 			article.ProfilePic = users[article.UserId].ProfilePic
+			articleReactions(&article, userId)
+			article.Saved = saved[article.Id]
 
 			articles = append(articles, &article)
 		}
 
 		sort.Slice(articles, func(i, j int) bool {
-			return articles[i].CreatedAt.After(*articles[j].CreatedAt)
+			return articleTime(articles[i]).After(articleTime(articles[j]))
 		})
 
 		JSON(&models.ArticleListResponse{
@@ -0,0 +1,71 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetBookmarkCollections
+func GetBookmarkCollections(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getBookmarkCollections"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId))
+
+		c, err := mongoDb.Collection("bookmarks").Aggregate(r.Context(), []bson.M{
+			{"$match": bson.M{"user_id": userId}},
+			{"$group": bson.M{"_id": "$collection", "count": bson.M{"$sum": 1}}},
+			{"$sort": bson.M{"_id": 1}},
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		var collections []*models.BookmarkCollection
+
+		for c.Next(r.Context()) {
+			var g struct {
+				Name  string `bson:"_id"`
+				Count int    `bson:"count"`
+			}
+			if err := c.Decode(&g); err != nil {
+				continue
+			}
+			collections = append(collections, &models.BookmarkCollection{
+				Name:  g.Name,
+				Count: g.Count,
+			})
+		}
+
+		JSON(&models.BookmarkCollectionListResponse{
+			Code:   200,
+			Result: collections,
+			Total:  len(collections),
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
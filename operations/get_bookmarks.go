@@ -0,0 +1,296 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+
+	"fr_book_api/models"
+
+	"github.com/thoas/go-funk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetBookmarks
+func GetBookmarks(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getBookmarks"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		collection := v.Query("collection").Optional().String()
+
+		targetType := v.Query("target_type").Optional().TargetType()
+
+		start := v.Query("start").Optional().Int()
+
+		limit := v.Query("limit").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("collection", collection), zap.Any("target_type", targetType))
+
+		start, limit = pageBounds(start, limit)
+
+		filter := bson.M{"user_id": userId}
+		if v.HasQuery("collection") {
+			filter["collection"] = collection
+		}
+		if v.HasQuery("target_type") {
+			filter["target_type"] = targetType
+		}
+
+		// items deleted after being saved are left out, and so are the ones
+		// the user may no longer see, before the page is cut
+		c, err := mongoDb.Collection("bookmarks").Find(r.Context(), filter, options.Find().SetSort(bson.M{"created_at": -1}))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		var all []*models.Bookmark
+		var postIds, articleIds []int
+		for c.Next(r.Context()) {
+			var b models.Bookmark
+			if err := c.Decode(&b); err != nil {
+				continue
+			}
+			switch b.TargetType {
+			case models.TargetTypePost:
+				postIds = append(postIds, b.TargetId)
+			case models.TargetTypeArticle:
+				articleIds = append(articleIds, b.TargetId)
+			}
+			all = append(all, &b)
+		}
+
+		posts, articles, err := bookmarkedItems(r.Context(), mongoDb, userId, postIds, articleIds)
+		if err != nil {
+			log.Error("Unable to get bookmarked items", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		// an item saved in several collections is prepared once for each
+		var bookmarks []*models.Bookmark
+		for _, b := range all {
+			if p, ok := posts[b.TargetId]; ok && b.TargetType == models.TargetTypePost {
+				post := *p
+				b.Post = &post
+			}
+			if a, ok := articles[b.TargetId]; ok && b.TargetType == models.TargetTypeArticle {
+				article := *a
+				b.Article = &article
+			}
+			if b.Post != nil || b.Article != nil {
+				bookmarks = append(bookmarks, b)
+			}
+		}
+
+		total := len(bookmarks)
+		if start >= total {
+			bookmarks = nil
+		} else if start+limit < total {
+			bookmarks = bookmarks[start : start+limit]
+		} else {
+			bookmarks = bookmarks[start:]
+		}
+
+		var page []*models.Post
+		for _, b := range bookmarks {
+			if b.Post != nil {
+				page = append(page, b.Post)
+			}
+			if b.Article != nil {
+				articleReactions(b.Article, userId)
+				b.Article.Saved = true
+			}
+		}
+		if err := preparePosts(r.Context(), mongoDb, userId, page); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		JSON(&models.BookmarkListResponse{
+			Code:   200,
+			Result: bookmarks,
+			Start:  start,
+			Total:  total,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// lookupUser returns the user with the given id, caching it in users.
+func lookupUser(ctx context.Context, mongoDb *mongo.Database, users map[int]models.User, id int) (models.User, bool) {
+	if u, ok := users[id]; ok {
+		return u, true
+	}
+	var u models.User
+	if err := mongoDb.Collection("users").FindOne(ctx, bson.M{"_id": id}).Decode(&u); err != nil {
+		return u, false
+	}
+	users[id] = u
+	return u, true
+}
+
+// bookmarkedItems returns the bookmarked posts and articles the user may
+// still see, along with their authors' names and pictures. Items of users
+// blocked either way are left out.
+func bookmarkedItems(ctx context.Context, mongoDb *mongo.Database, userId int, postIds, articleIds []int) (map[int]*models.Post, map[int]*models.Article, error) {
+	posts := make(map[int]*models.Post)
+	articles := make(map[int]*models.Article)
+
+	blocked, err := blockedIds(ctx, mongoDb, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authors := make(map[int]bool)
+	if len(postIds) > 0 {
+		visible, err := visiblePosts(ctx, mongoDb, userId, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		filter := bson.M{
+			"_id": bson.M{"$in": postIds},
+			"$and": []bson.M{visible, {"$or": []bson.M{
+				{"status": bson.M{"$nin": unpublished}},
+				{"user_id": userId},
+			}}},
+		}
+		if len(blocked) > 0 {
+			filter["user_id"] = bson.M{"$nin": blocked}
+		}
+		c, err := mongoDb.Collection("posts").Find(ctx, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		defer c.Close(ctx)
+
+		for c.Next(ctx) {
+			var p models.Post
+			if err := c.Decode(&p); err != nil {
+				continue
+			}
+			posts[p.Id] = &p
+			authors[p.UserId] = true
+		}
+	}
+
+	if len(articleIds) > 0 {
+		filter := bson.M{"_id": bson.M{"$in": articleIds}}
+		if len(blocked) > 0 {
+			filter["user_id"] = bson.M{"$nin": blocked}
+		}
+		c, err := mongoDb.Collection("articles").Find(ctx, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		defer c.Close(ctx)
+
+		for c.Next(ctx) {
+			var a models.Article
+			if err := c.Decode(&a); err != nil {
+				continue
+			}
+			if canSeeArticle(&a, userId, false) {
+				articles[a.Id] = &a
+				authors[a.UserId] = true
+			}
+		}
+	}
+
+	if len(authors) == 0 {
+		return posts, articles, nil
+	}
+	users := make(map[int]models.User)
+	c, err := mongoDb.Collection("users").Find(ctx, bson.M{"_id": bson.M{"$in": funk.Keys(authors)}},
+		options.Find().SetProjection(bson.M{"name": 1, "profile_pic": 1}))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer c.Close(ctx)
+
+	for c.Next(ctx) {
+		var u models.User
+		if err := c.Decode(&u); err != nil {
+			continue
+		}
+		users[u.Id] = u
+	}
+
+	// items of users who are gone are left out too
+	for id, p := range posts {
+		u, ok := users[p.UserId]
+		if !ok {
+			delete(posts, id)
+			continue
+		}
+		p.Name = u.Name
+		p.ProfilePic = u.ProfilePic
+	}
+	for id, a := range articles {
+		u, ok := users[a.UserId]
+		if !ok {
+			delete(articles, id)
+			continue
+		}
+		a.ProfilePic = u.ProfilePic
+	}
+	return posts, articles, nil
+}
+
+// canSeePost reports whether the user may see the post: it is published or
+// theirs, and shared with an audience they are in. Blocks are left to the
+// caller.
+func canSeePost(ctx context.Context, mongoDb *mongo.Database, p *models.Post, userId int) (bool, error) {
+	if isUnpublished(p.Status) && p.UserId != userId {
+		return false, nil
+	}
+	return inAudience(ctx, mongoDb, p.UserId, userId, p.Visibility, p.ListId)
+}
+
+// savedTargets returns the ids of all items of the given type the user
+// has bookmarked.
+func savedTargets(ctx context.Context, mongoDb *mongo.Database, userId int, targetType models.TargetType) (map[int]bool, error) {
+	c, err := mongoDb.Collection("bookmarks").Find(ctx, bson.M{
+		"user_id":     userId,
+		"target_type": targetType,
+	}, options.Find().SetProjection(bson.M{"target_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Close(ctx)
+
+	saved := make(map[int]bool)
+	for c.Next(ctx) {
+		var b models.Bookmark
+		if err := c.Decode(&b); err != nil {
+			continue
+		}
+		saved[b.TargetId] = true
+	}
+	return saved, nil
+}
+
+// -- end --
@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"net/http"
 
 	"fr_book_api/models"
@@ -18,13 +19,27 @@ import (
 func GetChats(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
 	oLog := logger.With(zap.String("op", "getChats"))
 	// -- init --
+	if mongoDb != nil {
+		_, err := mongoDb.Collection("chats").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys: bson.D{{Key: "from_id", Value: 1}, {Key: "to_id", Value: 1}, {Key: "created_at", Value: -1}},
+		})
+		if err != nil {
+			oLog.Error("Unable to create chat index", zap.Error(err))
+		}
+	}
 	// -- end --
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		v := models.NewValidator(r).Secret(sugar)
 
 		userId := v.Token("user_id").Int()
 
-		toId := v.Query("to_id").Int()
+		toId := v.Query("to_id").Optional().Int()
+
+		conversationId := v.Query("conversation_id").Optional().Int()
+
+		before := v.Query("before").Optional().Int()
+
+		limit := v.Query("limit").Optional().Int()
 
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
 		// -- code --
@@ -32,21 +47,51 @@ func GetChats(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Ha
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("to_id", toId))
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("to_id", toId), zap.Any("conversation_id", conversationId), zap.Any("before", before), zap.Any("limit", limit))
+
+		_, limit = pageBounds(0, limit)
+
+		// older messages are paged through by the id of the oldest one
+		// already loaded
+		var filter bson.M
+		if conversationId != 0 {
+			count, err := mongoDb.Collection("conversations").CountDocuments(r.Context(), bson.M{"_id": conversationId, "participant_ids": userId})
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if count == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			filter = bson.M{"conversation_id": conversationId}
+		} else if toId != 0 {
+			filter = bson.M{"$or": []bson.M{{"from_id": userId, "to_id": toId}, {"from_id": toId, "to_id": userId}}}
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if before > 0 {
+			filter["_id"] = bson.M{"$lt": before}
+		}
+		filter["deleted_for"] = bson.M{"$ne": userId}
 
-		var chats []*models.Chat
+		chats := []*models.Chat{}
 
-		c, err := mongoDb.Collection("chats").Find(r.Context(), bson.M{"$or": []bson.M{{"from_id": userId, "to_id": toId}, {"from_id": toId, "to_id": userId}}}, options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(10))
+		c, err := mongoDb.Collection("chats").Find(r.Context(), filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit)))
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
+		defer c.Close(r.Context())
+
 		for c.Next(r.Context()) {
 			var chat models.Chat
 			if err := c.Decode(&chat); err != nil {
 				continue
 			}
+			chat.DeletedFor = nil
 			chats = append(chats, &chat)
 		}
 
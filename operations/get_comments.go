@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"net/http"
 
 	"fr_book_api/models"
@@ -18,25 +19,74 @@ import (
 func GetComments(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
 	oLog := logger.With(zap.String("op", "getComments"))
 	// -- init --
+	if mongoDb != nil {
+		for _, keys := range []bson.D{
+			{{Key: "post_id", Value: 1}, {Key: "created_at", Value: -1}},
+			{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "created_at", Value: -1}},
+			{{Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}},
+		} {
+			mongoDb.Collection("comments").Indexes().CreateOne(context.Background(), mongo.IndexModel{Keys: keys})
+		}
+	}
 	// -- end --
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		v := models.NewValidator(r).Secret(sugar)
 
 		userId := v.Token("user_id").Int()
 
+		target := v.Path("target").String()
+
 		id := v.Path("id").Int()
 
+		parentId := v.Query("parent_id").Optional().Int()
+
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
 		// -- code --
 		if !v.Valid() {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("target", target), zap.Any("id", id), zap.Any("parent_id", parentId))
+
+		targetType, ok := targetFromPath(target)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if _, ok, err := targetOwner(r.Context(), mongoDb, targetType, id, userId); err != nil || !ok {
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+			} else {
+				w.WriteHeader(http.StatusNotFound)
+			}
+			return
+		}
+
+		// the latest top level comments, or the whole thread of one
+		filter := commentFilter(targetType, id)
+		opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(10)
+		if parentId != 0 {
+			filter["parent_id"] = parentId
+			opts = options.Find().SetSort(bson.M{"created_at": 1}).SetLimit(maxReplies)
+		} else {
+			filter["parent_id"] = bson.M{"$exists": false}
+		}
+
+		// comments of users blocked either way are left out
+		blocked, err := blockedIds(r.Context(), mongoDb, userId)
+		if err != nil {
+			log.Error("Unable to get blocks", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if len(blocked) > 0 {
+			filter["user_id"] = bson.M{"$nin": blocked}
+		}
 
 		var comments []*models.Comment
 
-		c, err := mongoDb.Collection("comments").Find(r.Context(), bson.M{"post_id": id}, options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(10))
+		c, err := mongoDb.Collection("comments").Find(r.Context(), filter, opts)
 
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -65,7 +115,9 @@ func GetComments(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http
 			comments = append(comments, &comment)
 		}
 
-		comments = funk.Reverse(comments).([]*models.Comment)
+		if parentId == 0 {
+			comments = funk.Reverse(comments).([]*models.Comment)
+		}
 
 		JSON(&models.CommentListResponse{
 			Code:   200,
@@ -76,4 +128,8 @@ func GetComments(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http
 }
 
 // -- extra --
+
+// maxReplies caps how many replies of a thread are returned.
+const maxReplies = 100
+
 // -- end --
@@ -0,0 +1,184 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+
+	"fr_book_api/actors"
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetConversations
+func GetConversations(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getConversations"))
+	// -- init --
+	if mongoDb != nil {
+		_, err := mongoDb.Collection("conversations").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "pair", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"pair": bson.M{"$exists": true}}),
+			},
+			{Keys: bson.D{{Key: "participant_ids", Value: 1}, {Key: "updated_at", Value: -1}}},
+		})
+		if err != nil {
+			oLog.Error("Unable to create conversations indexes", zap.Error(err))
+		}
+		_, err = mongoDb.Collection("chats").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys: bson.D{{Key: "conversation_id", Value: 1}, {Key: "_id", Value: -1}},
+		})
+		if err != nil {
+			oLog.Error("Unable to create chat conversation index", zap.Error(err))
+		}
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		start := v.Query("start").Optional().Int()
+
+		limit := v.Query("limit").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("start", start), zap.Any("limit", limit))
+
+		start, limit = pageBounds(start, limit)
+
+		filter := bson.M{"participant_ids": userId}
+		total, err := mongoDb.Collection("conversations").CountDocuments(r.Context(), filter)
+		if err != nil {
+			log.Error("Unable to count conversations", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		c, err := mongoDb.Collection("conversations").Find(r.Context(), filter, options.Find().
+			SetSort(bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}).
+			SetSkip(int64(start)).
+			SetLimit(int64(limit)))
+		if err != nil {
+			log.Error("Unable to find conversations", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		users := make(map[int]models.User)
+		convs := []*models.Conversation{}
+		for c.Next(r.Context()) {
+			var conv models.Conversation
+			if err := c.Decode(&conv); err != nil {
+				continue
+			}
+			viewConversation(r.Context(), mongoDb, users, &conv, userId)
+			convs = append(convs, &conv)
+		}
+
+		JSON(&models.ConversationListResponse{
+			Code:   200,
+			Result: convs,
+			Start:  start,
+			Total:  int(total),
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// viewConversation fills in the user's own read state and the profiles of
+// everyone else taking part.
+func viewConversation(ctx context.Context, mongoDb *mongo.Database, users map[int]models.User, conv *models.Conversation, userId int) {
+	for _, m := range conv.Members {
+		if m.UserId == userId {
+			conv.LastReadId = m.LastReadId
+			conv.UnreadCount = m.UnreadCount
+		}
+	}
+	for _, id := range conv.ParticipantIds {
+		if id == userId {
+			continue
+		}
+		if u, ok := lookupUser(ctx, mongoDb, users, id); ok {
+			u.Password = ""
+			u.Email = ""
+			u.Phone = ""
+			conv.Users = append(conv.Users, &u)
+		}
+	}
+}
+
+// markConversationRead moves the user's read marker to the latest message
+// of the conversation, clears their unread count and sends out a read
+// receipt. The marker is only moved if no message came in since the latest
+// one was looked up, so a count is never cleared for a message the user
+// didn't get to see; the lookup is retried instead. It returns nil if the
+// user doesn't take part in the conversation.
+func markConversationRead(ctx context.Context, mongoDb *mongo.Database, id, userId int) (*models.Conversation, error) {
+	for {
+		var conv models.Conversation
+		err := mongoDb.Collection("conversations").FindOne(ctx, bson.M{"_id": id, "participant_ids": userId}).Decode(&conv)
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		filter := bson.M{"_id": id}
+		lastId := 0
+		if conv.LastMessage != nil {
+			lastId = conv.LastMessage.Id
+			filter["last_message._id"] = lastId
+		} else {
+			filter["last_message"] = bson.M{"$exists": false}
+		}
+
+		res, err := mongoDb.Collection("conversations").UpdateOne(ctx, filter, bson.M{"$set": bson.M{
+			"members.$[me].last_read_id": lastId,
+			"members.$[me].unread_count": 0,
+		}}, options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+			bson.M{"me.user_id": userId},
+		}}))
+		if err != nil {
+			return nil, err
+		}
+		if res.MatchedCount == 0 {
+			continue
+		}
+
+		for _, m := range conv.Members {
+			if m.UserId == userId {
+				m.LastReadId = lastId
+				m.UnreadCount = 0
+			}
+		}
+
+		// the chat hub passes the read receipt on to everyone taking part
+		if hb := actors.HubById("chat"); hb != nil {
+			hb.Default(&models.ChatEvent{
+				Kind:           models.ChatEventTypeRead,
+				ConversationId: id,
+				UserId:         userId,
+				ChatId:         lastId,
+			}, nil)
+		}
+		return &conv, nil
+	}
+}
+
+// -- end --
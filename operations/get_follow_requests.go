@@ -0,0 +1,72 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetFollowRequests
+func GetFollowRequests(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getFollowRequests"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId))
+
+		c, err := mongoDb.Collection("follows").Find(r.Context(), bson.M{
+			"to_id":   userId,
+			"pending": true,
+		}, options.Find().SetSort(bson.M{"created_at": -1}))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		users := make(map[int]models.User)
+		requests := []*models.User{}
+		for c.Next(r.Context()) {
+			var f models.Follow
+			if err := c.Decode(&f); err != nil {
+				continue
+			}
+			u, ok := lookupUser(r.Context(), mongoDb, users, f.FromId)
+			if !ok {
+				continue
+			}
+			u.Password = ""
+			u.Status = models.ReqStatusTakeAction
+			u.ReqId = f.Id
+			requests = append(requests, &u)
+		}
+
+		JSON(&models.UserListResponse{
+			Code:   200,
+			Result: requests,
+			Total:  len(requests),
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -0,0 +1,182 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetFollowers
+func GetFollowers(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getFollowers"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		start := v.Query("start").Optional().Int()
+
+		limit := v.Query("limit").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		writeFollowList(w, r, mongoDb, log, userId, id, start, limit, true)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// writeFollowList writes a page of the followers of a user, or of the users
+// they follow. Only approved follows are listed, and for private accounts
+// only to the account and its followers.
+func writeFollowList(w http.ResponseWriter, r *http.Request, mongoDb *mongo.Database, log *zap.Logger, userId, id, start, limit int, followers bool) {
+	start, limit = pageBounds(start, limit)
+
+	var owner models.User
+	if err := mongoDb.Collection("users").FindOne(r.Context(), bson.M{"_id": id}).Decode(&owner); err != nil {
+		if err == mongo.ErrNoDocuments {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ok, err := canSeeProfile(r.Context(), mongoDb, userId, &owner)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	filter := bson.M{"to_id": id, "pending": bson.M{"$ne": true}}
+	other := func(f *models.Follow) int { return f.FromId }
+	if !followers {
+		filter = bson.M{"from_id": id, "pending": bson.M{"$ne": true}}
+		other = func(f *models.Follow) int { return f.ToId }
+	}
+
+	total, err := mongoDb.Collection("follows").CountDocuments(r.Context(), filter)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c, err := mongoDb.Collection("follows").Find(r.Context(), filter, options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(int64(start)).
+		SetLimit(int64(limit)))
+	if err != nil {
+		log.Error("Unable to list follows", zap.Error(err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	defer c.Close(r.Context())
+
+	var ids []int
+	for c.Next(r.Context()) {
+		var f models.Follow
+		if err := c.Decode(&f); err != nil {
+			continue
+		}
+		ids = append(ids, other(&f))
+	}
+
+	follows, err := followStates(r.Context(), mongoDb, userId, ids)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	users := make(map[int]models.User)
+	result := []*models.User{}
+	for _, uid := range ids {
+		u, ok := lookupUser(r.Context(), mongoDb, users, uid)
+		if !ok {
+			continue
+		}
+		u.Password = ""
+		applyFollowState(&u, follows)
+		result = append(result, &u)
+	}
+
+	JSON(&models.UserListResponse{
+		Code:   200,
+		Result: result,
+		Start:  start,
+		Total:  int(total),
+	}, w)
+}
+
+// canSeeProfile reports whether the user may see who a user follows and is
+// followed by: everyone may for public accounts, only approved followers
+// for private ones.
+func canSeeProfile(ctx context.Context, mongoDb *mongo.Database, userId int, owner *models.User) (bool, error) {
+	if !owner.Private || owner.Id == userId {
+		return true, nil
+	}
+	count, err := mongoDb.Collection("follows").CountDocuments(ctx, bson.M{
+		"from_id": userId,
+		"to_id":   owner.Id,
+		"pending": bson.M{"$ne": true},
+	})
+	return count > 0, err
+}
+
+// followStates returns the follows of the user on any of the given users,
+// keyed by the followed user.
+func followStates(ctx context.Context, mongoDb *mongo.Database, userId int, ids []int) (map[int]*models.Follow, error) {
+	ret := make(map[int]*models.Follow)
+	if len(ids) == 0 {
+		return ret, nil
+	}
+
+	c, err := mongoDb.Collection("follows").Find(ctx, bson.M{"from_id": userId, "to_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Close(ctx)
+
+	for c.Next(ctx) {
+		var f models.Follow
+		if err := c.Decode(&f); err != nil {
+			continue
+		}
+		ret[f.ToId] = &f
+	}
+	return ret, nil
+}
+
+// applyFollowState marks whether the user is followed, or asked to be.
+func applyFollowState(u *models.User, follows map[int]*models.Follow) {
+	if f, ok := follows[u.Id]; ok {
+		u.Followed = !f.Pending
+		u.FollowPending = f.Pending
+	}
+}
+
+// -- end --
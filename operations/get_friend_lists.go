@@ -0,0 +1,165 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetFriendLists
+func GetFriendLists(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getFriendLists"))
+	// -- init --
+	if mongoDb != nil {
+		_, err := mongoDb.Collection("friend_lists").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{Keys: bson.D{{Key: "member_ids", Value: 1}}},
+		})
+		if err != nil {
+			oLog.Error("Unable to create friend lists indexes", zap.Error(err))
+		}
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId))
+
+		c, err := mongoDb.Collection("friend_lists").Find(r.Context(), bson.M{"user_id": userId}, options.Find().SetSort(bson.M{"name": 1}))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		lists := []*models.FriendList{}
+		for c.Next(r.Context()) {
+			var l models.FriendList
+			if err := c.Decode(&l); err != nil {
+				continue
+			}
+			lists = append(lists, &l)
+		}
+
+		JSON(&models.FriendListListResponse{
+			Code:   200,
+			Result: lists,
+			Total:  len(lists),
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// maxFriendListName caps the length of a friend list's name.
+const maxFriendListName = 50
+
+// listMembers checks that everyone to be put on a list of the user is
+// their friend, and drops duplicates.
+func listMembers(ctx context.Context, mongoDb *mongo.Database, userId int, ids []int) ([]int, bool, error) {
+	friends, err := friendIds(ctx, mongoDb, userId)
+	if err != nil {
+		return nil, false, err
+	}
+	isFriend := make(map[int]bool)
+	for _, f := range friends {
+		isFriend[f] = true
+	}
+
+	members := []int{}
+	seen := make(map[int]bool)
+	for _, id := range ids {
+		if !isFriend[id] {
+			return nil, false, nil
+		}
+		if !seen[id] {
+			seen[id] = true
+			members = append(members, id)
+		}
+	}
+	return members, true, nil
+}
+
+// ownsFriendList reports whether the list exists and belongs to the user.
+func ownsFriendList(ctx context.Context, mongoDb *mongo.Database, userId, listId int) (bool, error) {
+	count, err := mongoDb.Collection("friend_lists").CountDocuments(ctx, bson.M{"_id": listId, "user_id": userId})
+	return count > 0, err
+}
+
+// memberOfLists returns the ids of all friend lists the user is on.
+func memberOfLists(ctx context.Context, mongoDb *mongo.Database, userId int) ([]int, error) {
+	c, err := mongoDb.Collection("friend_lists").Find(ctx, bson.M{"member_ids": userId}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Close(ctx)
+
+	ids := []int{}
+	for c.Next(ctx) {
+		var l models.FriendList
+		if err := c.Decode(&l); err != nil {
+			continue
+		}
+		ids = append(ids, l.Id)
+	}
+	return ids, nil
+}
+
+// inAudience reports whether the user may see what the author shared with
+// the given audience.
+func inAudience(ctx context.Context, mongoDb *mongo.Database, authorId, userId int, visibility models.Visibility, listId int) (bool, error) {
+	if authorId == userId {
+		return true, nil
+	}
+	switch visibility {
+	case models.VisibilityFriends:
+		return areFriends(ctx, mongoDb, authorId, userId)
+	case models.VisibilityList:
+		count, err := mongoDb.Collection("friend_lists").CountDocuments(ctx, bson.M{
+			"_id":        listId,
+			"user_id":    authorId,
+			"member_ids": userId,
+		})
+		return count > 0, err
+	}
+	return true, nil
+}
+
+// leaveFriendLists takes two users who are no longer friends off each
+// other's lists.
+func leaveFriendLists(ctx context.Context, mongoDb *mongo.Database, a, b int) error {
+	for _, p := range [][2]int{{a, b}, {b, a}} {
+		_, err := mongoDb.Collection("friend_lists").UpdateMany(ctx, bson.M{
+			"user_id":    p[0],
+			"member_ids": p[1],
+		}, bson.M{"$pull": bson.M{"member_ids": p[1]}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// -- end --
@@ -5,7 +5,9 @@ import (
 
 	"fr_book_api/models"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 	// -- imports --
 	// -- end --
@@ -29,8 +31,31 @@ func GetFriendRequests(sugar string, mongoDb *mongo.Database, logger *zap.Logger
 		}
 		log.Debug("Start Operation", zap.Any("user_id", userId))
 
+		// both the requests to answer and those waiting on others
+		c, err := mongoDb.Collection("friend_requests").Find(r.Context(), bson.M{"$or": []bson.M{
+			{"from_id": userId},
+			{"to_id": userId},
+		}}, options.Find().SetSort(bson.M{"created_at": -1}))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		requests := []*models.FriendRequest{}
+		for c.Next(r.Context()) {
+			var fr models.FriendRequest
+			if err := c.Decode(&fr); err != nil {
+				continue
+			}
+			requests = append(requests, &fr)
+		}
+
 		JSON(&models.FriendRequestListResponse{
-			Code: 200,
+			Code:   200,
+			Result: requests,
+			Total:  len(requests),
 		}, w)
 		// -- end --
 	})
@@ -0,0 +1,226 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetFriendSuggestions
+func GetFriendSuggestions(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getFriendSuggestions"))
+	// -- init --
+	if mongoDb != nil {
+		for _, coll := range []string{"friends", "friend_requests", "blocks"} {
+			_, err := mongoDb.Collection(coll).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "from_id", Value: 1}}},
+				{Keys: bson.D{{Key: "to_id", Value: 1}}},
+			})
+			if err != nil {
+				oLog.Error("Unable to create indexes", zap.String("collection", coll), zap.Error(err))
+			}
+		}
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		start := v.Query("start").Optional().Int()
+
+		limit := v.Query("limit").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId))
+
+		start, limit = pageBounds(start, limit)
+
+		friends, err := friendIds(r.Context(), mongoDb, userId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		ignore, err := ignoredIds(r.Context(), mongoDb, userId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		ignore = append(ignore, friends...)
+
+		if len(friends) == 0 {
+			JSON(&models.UserListResponse{
+				Code:   200,
+				Result: []*models.User{},
+				Start:  start,
+			}, w)
+			return
+		}
+
+		// every friendship of a friend leads to a friend of a friend, who
+		// is ranked by how many such friendships there are
+		isFriend := bson.M{"$in": bson.A{"$from_id", friends}}
+		c, err := mongoDb.Collection("friends").Aggregate(r.Context(), mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"$or": []bson.M{
+				{"from_id": bson.M{"$in": friends}},
+				{"to_id": bson.M{"$in": friends}},
+			}}}},
+			{{Key: "$project", Value: bson.M{
+				"mutual": bson.M{"$cond": bson.A{isFriend, "$from_id", "$to_id"}},
+				"other":  bson.M{"$cond": bson.A{isFriend, "$to_id", "$from_id"}},
+			}}},
+			{{Key: "$match", Value: bson.M{"other": bson.M{"$nin": ignore}}}},
+			{{Key: "$group", Value: bson.M{"_id": "$other", "mutual": bson.M{"$addToSet": "$mutual"}}}},
+			{{Key: "$project", Value: bson.M{
+				"count":  bson.M{"$size": "$mutual"},
+				"mutual": bson.M{"$slice": bson.A{"$mutual", mutualSample}},
+			}}},
+			{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+			{{Key: "$facet", Value: bson.M{
+				"total": bson.A{bson.M{"$count": "n"}},
+				"users": bson.A{bson.M{"$skip": start}, bson.M{"$limit": limit}},
+			}}},
+		})
+		if err != nil {
+			log.Error("Unable to find suggestions", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		var page struct {
+			Total []struct {
+				N int `bson:"n"`
+			} `bson:"total"`
+			Users []struct {
+				Id     int   `bson:"_id"`
+				Count  int   `bson:"count"`
+				Mutual []int `bson:"mutual"`
+			} `bson:"users"`
+		}
+		if c.Next(r.Context()) {
+			if err := c.Decode(&page); err != nil {
+				log.Error("Unable to decode suggestions", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		users := make(map[int]models.User)
+		result := []*models.User{}
+		for _, s := range page.Users {
+			user, ok := lookupUser(r.Context(), mongoDb, users, s.Id)
+			if !ok {
+				continue
+			}
+			user.Password = ""
+			user.MutualFriendsCount = s.Count
+			user.MutualAvatars = mutualAvatars(r.Context(), mongoDb, users, s.Mutual)
+			result = append(result, &user)
+		}
+
+		total := 0
+		if len(page.Total) > 0 {
+			total = page.Total[0].N
+		}
+
+		JSON(&models.UserListResponse{
+			Code:   200,
+			Result: result,
+			Start:  start,
+			Total:  total,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// mutualSample is how many mutual friends are shown with a suggestion.
+const mutualSample = 3
+
+// ignoredIds returns the user and everyone who should not be suggested to
+// them: users with a pending friend request either way, and users blocked
+// either way.
+func ignoredIds(ctx context.Context, mongoDb *mongo.Database, userId int) ([]int, error) {
+	requested, err := relatedIds(ctx, mongoDb, "friend_requests", userId)
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := blockedIds(ctx, mongoDb, userId)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]int{userId}, requested...), blocked...), nil
+}
+
+// blockedIds returns everyone the user has blocked or is blocked by.
+func blockedIds(ctx context.Context, mongoDb *mongo.Database, userId int) ([]int, error) {
+	return relatedIds(ctx, mongoDb, "blocks", userId)
+}
+
+// relatedIds returns the other side of every from_id/to_id entry of the
+// collection the user takes part in.
+func relatedIds(ctx context.Context, mongoDb *mongo.Database, coll string, userId int) ([]int, error) {
+	c, err := mongoDb.Collection(coll).Find(ctx, bson.M{"$or": []bson.M{
+		{"from_id": userId},
+		{"to_id": userId},
+	}}, options.Find().SetProjection(bson.M{"from_id": 1, "to_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Close(ctx)
+
+	var ids []int
+	for c.Next(ctx) {
+		var e models.FriendEntry
+		if err := c.Decode(&e); err != nil {
+			continue
+		}
+		if e.FromId == userId {
+			ids = append(ids, e.ToId)
+		} else {
+			ids = append(ids, e.FromId)
+		}
+	}
+	return ids, nil
+}
+
+// blockedBetween reports whether either user has blocked the other.
+func blockedBetween(ctx context.Context, mongoDb *mongo.Database, a, b int) (bool, error) {
+	count, err := mongoDb.Collection("blocks").CountDocuments(ctx, bson.M{"$or": []bson.M{
+		{"from_id": a, "to_id": b},
+		{"from_id": b, "to_id": a},
+	}})
+	return count > 0, err
+}
+
+// mutualAvatars returns the profile pictures of those mutual friends who
+// have one.
+func mutualAvatars(ctx context.Context, mongoDb *mongo.Database, users map[int]models.User, ids []int) []string {
+	var avatars []string
+	for _, id := range ids {
+		if u, ok := lookupUser(ctx, mongoDb, users, id); ok && u.ProfilePic != "" {
+			avatars = append(avatars, u.ProfilePic)
+		}
+	}
+	return avatars
+}
+
+// -- end --
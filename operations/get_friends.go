@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"net/http"
 
 	"fr_book_api/models"
@@ -67,4 +68,41 @@ func GetFriends(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.
 }
 
 // -- extra --
+
+// friendIds returns the ids of everyone the user is friends with.
+func friendIds(ctx context.Context, mongoDb *mongo.Database, userId int) ([]int, error) {
+	c, err := mongoDb.Collection("friends").Find(ctx, bson.M{"$or": []bson.M{
+		{"from_id": userId},
+		{"to_id": userId},
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Close(ctx)
+
+	var ids []int
+	for c.Next(ctx) {
+		var f models.FriendEntry
+		if err := c.Decode(&f); err != nil {
+			continue
+		}
+		if f.FromId == userId {
+			ids = append(ids, f.ToId)
+		} else {
+			ids = append(ids, f.FromId)
+		}
+	}
+	return ids, nil
+}
+
+// areFriends reports whether the two users are friends.
+func areFriends(ctx context.Context, mongoDb *mongo.Database, a, b int) (bool, error) {
+	count, err := mongoDb.Collection("friends").CountDocuments(ctx, bson.M{"$or": []bson.M{
+		{"from_id": a, "to_id": b},
+		{"from_id": b, "to_id": a},
+	}})
+	return count > 0, err
+}
+
 // -- end --
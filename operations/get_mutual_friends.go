@@ -0,0 +1,97 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetMutualFriends
+func GetMutualFriends(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getMutualFriends"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		blocked, err := blockedBetween(r.Context(), mongoDb, userId, id)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if blocked {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		mine, err := friendIds(r.Context(), mongoDb, userId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		theirs, err := friendIds(r.Context(), mongoDb, id)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		isMine := make(map[int]bool)
+		for _, f := range mine {
+			isMine[f] = true
+		}
+		var mutual []int
+		for _, f := range theirs {
+			if isMine[f] {
+				mutual = append(mutual, f)
+			}
+		}
+
+		friends := []*models.User{}
+		if len(mutual) > 0 {
+			c, err := mongoDb.Collection("users").Find(r.Context(), bson.M{"_id": bson.M{"$in": mutual}})
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			defer c.Close(r.Context())
+
+			for c.Next(r.Context()) {
+				var user models.User
+				if err := c.Decode(&user); err != nil {
+					continue
+				}
+				user.Password = ""
+				friends = append(friends, &user)
+			}
+		}
+
+		JSON(&models.UserListResponse{
+			Code:   200,
+			Result: friends,
+			Total:  len(friends),
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -5,7 +5,6 @@ import (
 
 	"fr_book_api/models"
 
-	"github.com/thoas/go-funk"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
@@ -34,13 +33,18 @@ func GetNotFriends(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 		pendingResp := make(map[int]int)
 		takeAction := make(map[int]int)
 
-		frc, err := mongoDb.Collection("friend_requests").Find(r.Context(), bson.M{})
+		frc, err := mongoDb.Collection("friend_requests").Find(r.Context(), bson.M{"$or": []bson.M{
+			{"from_id": userId},
+			{"to_id": userId},
+		}})
 
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
+		defer frc.Close(r.Context())
+
 		for frc.Next(r.Context()) {
 			var fr models.FriendRequest
 			if err := frc.Decode(&fr); err != nil {
@@ -54,30 +58,23 @@ func GetNotFriends(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 			}
 		}
 
-		idsToIgnore := []int{userId}
-
-		fc, err := mongoDb.Collection("friends").Find(r.Context(), bson.M{})
+		idsToIgnore, err := friendIds(r.Context(), mongoDb, userId)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		idsToIgnore = append(idsToIgnore, userId)
 
-		for fc.Next(r.Context()) {
-			var fr models.FriendEntry
-			if err := fc.Decode(&fr); err != nil {
-				continue
-			}
-			if fr.FromId == userId {
-				idsToIgnore = append(idsToIgnore, fr.ToId)
-			}
-			if fr.ToId == userId {
-				idsToIgnore = append(idsToIgnore, fr.FromId)
-			}
+		blocked, err := blockedIds(r.Context(), mongoDb, userId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
 		}
+		idsToIgnore = append(idsToIgnore, blocked...)
 
 		var friends []*models.User
 
-		c, err := mongoDb.Collection("users").Find(r.Context(), bson.M{})
+		c, err := mongoDb.Collection("users").Find(r.Context(), bson.M{"_id": bson.M{"$nin": idsToIgnore}})
 
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -91,9 +88,6 @@ func GetNotFriends(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 			if err := c.Decode(&user); err != nil {
 				continue
 			}
-			if funk.Contains(idsToIgnore, user.Id) {
-				continue
-			}
 
 			if _, ok := pendingResp[user.Id]; ok {
 				user.Status = models.ReqStatusPending
@@ -0,0 +1,70 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetPostDrafts
+func GetPostDrafts(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getPostDrafts"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId))
+
+		c, err := mongoDb.Collection("posts").Find(r.Context(), bson.M{
+			"user_id": userId,
+			"status":  bson.M{"$in": unpublished},
+		}, options.Find().SetSort(bson.M{"created_at": -1}))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		var posts []*models.Post
+
+		for c.Next(r.Context()) {
+			var p models.Post
+			if err := c.Decode(&p); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			posts = append(posts, &p)
+		}
+
+		if err := preparePosts(r.Context(), mongoDb, userId, posts); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		JSON(&models.PostListResponse{
+			Code:   200,
+			Result: posts,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -1,7 +1,11 @@
 package operations
 
 import (
+	"context"
+	"math"
 	"net/http"
+	"sort"
+	"time"
 
 	"fr_book_api/models"
 
@@ -18,21 +22,62 @@ import (
 func GetPosts(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
 	oLog := logger.With(zap.String("op", "getPosts"))
 	// -- init --
+	if mongoDb != nil {
+		mongoDb.Collection("posts").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys: bson.M{"rank": -1},
+		})
+		// the Background hub ranks posts by the weights and age of their rank
+		mongoDb.Collection("posts").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys: bson.M{"rank_version": 1},
+		})
+		mongoDb.Collection("posts").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys: bson.M{"created_at": -1},
+		})
+		mongoDb.Collection("affinities").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "other_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+	}
 	// -- end --
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		v := models.NewValidator(r).Secret(sugar)
 
 		userId := v.Token("user_id").Int()
 
+		sortBy := v.Query("sort").Def("latest").String()
+
+		following := v.Query("following").Optional().Bool()
+
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
 		// -- code --
 		if !v.Valid() {
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
-		log.Debug("Start Operation")
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("sort", sortBy), zap.Any("following", following))
+
+		if sortBy != "latest" && sortBy != "top" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
 
-		c, err := mongoDb.Collection("posts").Find(r.Context(), bson.M{}, options.Find().SetSort(bson.M{"created_at": -1}))
+		weights := models.GetFeedWeights()
+
+		// the ranked feed only scores the best ranked candidates instead of
+		// every post
+		findOptions := options.Find().SetSort(bson.M{"created_at": -1})
+		if sortBy == "top" {
+			findOptions = options.Find().SetSort(bson.M{"rank": -1}).SetLimit(int64(weights.Candidates))
+		}
+
+		filter, err := visiblePosts(r.Context(), mongoDb, userId, following)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		filter["status"] = bson.M{"$nin": unpublished}
+
+		c, err := mongoDb.Collection("posts").Find(r.Context(), filter, findOptions)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
@@ -49,12 +94,6 @@ func GetPosts(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Ha
 				w.WriteHeader(http.StatusInternalServerError)
 				return
 			}
-			p.LikesCount = len(p.Likes)
-
-			if funk.Contains(p.Likes, userId) {
-				p.Liked = true
-			}
-
 			if _, ok := users[p.UserId]; !ok {
 				var u models.User
 				if err := mongoDb.Collection("users").FindOne(r.Context(), bson.M{"_id": p.UserId}).Decode(&u); err != nil {
@@ -65,11 +104,29 @@ func GetPosts(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Ha
 
 			p.Name = users[p.UserId].Name
 			p.ProfilePic = users[p.UserId].ProfilePic
-			p.Likes = nil
 
 			posts = append(posts, &p)
 		}
 
+		if err := preparePosts(r.Context(), mongoDb, userId, posts); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if sortBy == "top" {
+			affinities, err := feedAffinities(r.Context(), mongoDb, userId)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			for _, p := range posts {
+				p.Score = p.Rank + weights.Affinity*math.Log10(1+affinities[p.UserId])
+			}
+			sort.SliceStable(posts, func(i, j int) bool {
+				return posts[i].Score > posts[j].Score
+			})
+		}
+
 		JSON(&models.PostListResponse{
 			Code:   200,
 			Result: posts,
@@ -79,4 +136,147 @@ func GetPosts(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Ha
 }
 
 // -- extra --
+
+// bumpAffinity records that userId chatted or otherwise interacted with
+// otherId, which lifts otherId's posts in userId's ranked feed.
+func bumpAffinity(ctx context.Context, mongoDb *mongo.Database, userId, otherId int, field string) {
+	if userId == otherId {
+		return
+	}
+	mongoDb.Collection("affinities").UpdateOne(ctx, bson.M{
+		"user_id":  userId,
+		"other_id": otherId,
+	}, bson.M{"$inc": bson.M{field: 1}}, options.Update().SetUpsert(true))
+}
+
+// feedAffinities returns how strongly the user is connected to each author,
+// counting chats and interactions, with friendship worth one interaction.
+func feedAffinities(ctx context.Context, mongoDb *mongo.Database, userId int) (map[int]float64, error) {
+	ret := make(map[int]float64)
+
+	friends, err := friendIds(ctx, mongoDb, userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range friends {
+		ret[f] = 1
+	}
+
+	c, err := mongoDb.Collection("affinities").Find(ctx, bson.M{"user_id": userId})
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Close(ctx)
+
+	for c.Next(ctx) {
+		var a models.Affinity
+		if err := c.Decode(&a); err != nil {
+			continue
+		}
+		ret[a.OtherId] += float64(a.Chats + a.Interactions)
+	}
+	return ret, nil
+}
+
+// visiblePosts returns the filter matching the posts the user may see:
+// public posts, their own, those their friends shared with friends and
+// those shared with a list they're on. Limited to following, only the posts
+// of the user, their friends and the users they follow are matched.
+func visiblePosts(ctx context.Context, mongoDb *mongo.Database, userId int, following bool) (bson.M, error) {
+	friends, err := friendIds(ctx, mongoDb, userId)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := memberOfLists(ctx, mongoDb, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"$or": []bson.M{
+		{"visibility": bson.M{"$exists": false}},
+		{"user_id": userId},
+		{"visibility": models.VisibilityFriends, "user_id": bson.M{"$in": friends}},
+		{"visibility": models.VisibilityList, "list_id": bson.M{"$in": lists}},
+	}}
+	if following {
+		followed, err := followingIds(ctx, mongoDb, userId)
+		if err != nil {
+			return nil, err
+		}
+		authors := append(append([]int{userId}, friends...), followed...)
+		filter["user_id"] = bson.M{"$in": authors}
+	}
+	return filter, nil
+}
+
+// preparePosts fills in the viewer specific parts of posts: whether the
+// user liked and saved them and how they voted in polls. It's used wherever
+// posts are returned.
+func preparePosts(ctx context.Context, mongoDb *mongo.Database, userId int, posts []*models.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	saved, err := savedTargets(ctx, mongoDb, userId, models.TargetTypePost)
+	if err != nil {
+		return err
+	}
+
+	var polls []int
+	for _, p := range posts {
+		if p.Poll != nil {
+			polls = append(polls, p.Id)
+		}
+	}
+	votes := make(map[int][]int)
+	if len(polls) > 0 {
+		c, err := mongoDb.Collection("poll_votes").Find(ctx, bson.M{"user_id": userId, "post_id": bson.M{"$in": polls}})
+		if err != nil {
+			return err
+		}
+
+		defer c.Close(ctx)
+
+		for c.Next(ctx) {
+			var vote models.PollVote
+			if err := c.Decode(&vote); err != nil {
+				continue
+			}
+			votes[vote.PostId] = vote.OptionIds
+		}
+	}
+
+	ct := time.Now()
+	for _, p := range posts {
+		p.LikesCount = len(p.Likes)
+		p.Liked = funk.Contains(p.Likes, userId)
+		p.Likes = nil
+		p.Saved = saved[p.Id]
+		if p.Poll != nil {
+			preparePoll(p, userId, votes[p.Id], ct)
+		}
+	}
+	return nil
+}
+
+// preparePoll fills in the viewer specific parts of a poll post. Results
+// stay hidden from users who have not voted yet when the author asked for
+// it, until the poll closes.
+func preparePoll(p *models.Post, userId int, myVotes []int, ct time.Time) {
+	poll := p.Poll
+	if poll.ClosesAt != nil && !poll.ClosesAt.After(ct) {
+		poll.Closed = true
+	}
+	poll.Voted = len(myVotes) > 0
+	poll.MyVotes = myVotes
+
+	if poll.HideResults && !poll.Voted && !poll.Closed && p.UserId != userId {
+		poll.TotalVotes = 0
+		for _, o := range poll.Options {
+			o.Votes = 0
+		}
+	}
+}
+
 // -- end --
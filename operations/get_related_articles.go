@@ -0,0 +1,119 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetRelatedArticles
+func GetRelatedArticles(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getRelatedArticles"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		userType := v.Token("user_type").Optional().UserType()
+
+		id := v.Path("id").Int()
+
+		limit := v.Query("limit").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		var article models.Article
+		if err := mongoDb.Collection("articles").FindOne(r.Context(), bson.M{"_id": id}).Decode(&article); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if !canSeeArticle(&article, userId, userType == models.UserTypeAdmin) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, limit = pageBounds(0, limit)
+
+		var articles []*models.Article
+		if len(article.Tags) == 0 {
+			JSON(&models.ArticleListResponse{
+				Code:   200,
+				Result: articles,
+			}, w)
+			return
+		}
+
+		// the more tags an article shares, the more related it is
+		c, err := mongoDb.Collection("articles").Aggregate(r.Context(), mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{
+				"_id":    bson.M{"$ne": id},
+				"tags":   bson.M{"$in": article.Tags},
+				"status": bson.M{"$nin": unpublished},
+			}}},
+			{{Key: "$addFields", Value: bson.M{"overlap": bson.M{"$size": bson.M{"$setIntersection": bson.A{"$tags", article.Tags}}}}}},
+			{{Key: "$sort", Value: bson.D{{Key: "overlap", Value: -1}, {Key: "published_at", Value: -1}, {Key: "created_at", Value: -1}}}},
+			{{Key: "$limit", Value: limit}},
+			{{Key: "$project", Value: bson.M{"overlap": 0}}},
+		})
+		if err != nil {
+			log.Error("Unable to get related articles", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		saved, err := savedTargets(r.Context(), mongoDb, userId, models.TargetTypeArticle)
+		if err != nil {
+			log.Error("Unable to get bookmarks", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		users := make(map[int]models.User)
+		for c.Next(r.Context()) {
+			var a models.Article
+			if err := c.Decode(&a); err != nil {
+				log.Error("Unable to decode article", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			author, ok := lookupUser(r.Context(), mongoDb, users, a.UserId)
+			if !ok {
+				continue
+			}
+			a.ProfilePic = author.ProfilePic
+			articleReactions(&a, userId)
+			a.Saved = saved[a.Id]
+			articles = append(articles, &a)
+		}
+
+		JSON(&models.ArticleListResponse{
+			Code:   200,
+			Result: articles,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
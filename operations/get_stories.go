@@ -0,0 +1,130 @@
+package operations
+
+import (
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetStories
+func GetStories(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getStories"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId))
+
+		authors, err := friendIds(r.Context(), mongoDb, userId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		authors = append(authors, userId)
+
+		lists, err := memberOfLists(r.Context(), mongoDb, userId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		// the TTL index removes expired stories lazily, so filter them here too
+		c, err := mongoDb.Collection("stories").Find(r.Context(), bson.M{
+			"user_id":    bson.M{"$in": authors},
+			"expires_at": bson.M{"$gt": time.Now()},
+			"$or": []bson.M{
+				{"list_id": bson.M{"$exists": false}},
+				{"list_id": bson.M{"$in": lists}},
+				{"user_id": userId},
+			},
+		}, options.Find().SetSort(bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		var stories []*models.Story
+		var ids []int
+		users := make(map[int]models.User)
+
+		for c.Next(r.Context()) {
+			var s models.Story
+			if err := c.Decode(&s); err != nil {
+				continue
+			}
+
+			if _, ok := users[s.UserId]; !ok {
+				var u models.User
+				if err := mongoDb.Collection("users").FindOne(r.Context(), bson.M{"_id": s.UserId}).Decode(&u); err != nil {
+					continue
+				}
+				users[s.UserId] = u
+			}
+
+			s.Name = users[s.UserId].Name
+			s.ProfilePic = users[s.UserId].ProfilePic
+
+			// only the author gets to see how many people watched
+			if s.UserId != userId {
+				s.ViewsCount = 0
+			}
+
+			stories = append(stories, &s)
+			ids = append(ids, s.Id)
+		}
+
+		if len(ids) > 0 {
+			vc, err := mongoDb.Collection("story_views").Find(r.Context(), bson.M{
+				"story_id": bson.M{"$in": ids},
+				"user_id":  userId,
+			})
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			defer vc.Close(r.Context())
+
+			viewed := make(map[int]bool)
+			for vc.Next(r.Context()) {
+				var sv models.StoryView
+				if err := vc.Decode(&sv); err != nil {
+					continue
+				}
+				viewed[sv.StoryId] = true
+			}
+
+			for _, s := range stories {
+				s.Viewed = viewed[s.Id]
+			}
+		}
+
+		JSON(&models.StoryListResponse{
+			Code:   200,
+			Result: stories,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
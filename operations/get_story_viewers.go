@@ -0,0 +1,79 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetStoryViewers
+func GetStoryViewers(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getStoryViewers"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		count, err := mongoDb.Collection("stories").CountDocuments(r.Context(), bson.M{"_id": id, "user_id": userId})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if count == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		c, err := mongoDb.Collection("story_views").Find(r.Context(), bson.M{"story_id": id}, options.Find().SetSort(bson.M{"created_at": -1}))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		var viewers []*models.User
+
+		for c.Next(r.Context()) {
+			var sv models.StoryView
+			if err := c.Decode(&sv); err != nil {
+				continue
+			}
+			var user models.User
+			if err := mongoDb.Collection("users").FindOne(r.Context(), bson.M{"_id": sv.UserId}).Decode(&user); err != nil {
+				continue
+			}
+			user.Password = ""
+			viewers = append(viewers, &user)
+		}
+
+		JSON(&models.UserListResponse{
+			Code:   200,
+			Result: viewers,
+			Total:  len(viewers),
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -0,0 +1,53 @@
+package operations
+
+import (
+	"net/http"
+	"net/url"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetTagFeed
+func GetTagFeed(mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getTagFeed"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r)
+
+		tag := v.Path("tag").String()
+
+		format := v.Path("format").String()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("tag", tag), zap.Any("format", format))
+
+		tags := models.NormalizeTags(tag)
+		if len(tags) != 1 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		writeArticleFeed(w, r, mongoDb, log, &articleFeed{
+			format: format,
+			title:  "frBook articles tagged " + tags[0],
+			path:   "/articles/tags/" + url.PathEscape(tags[0]) + "/feed",
+			filter: bson.M{"tags": tags[0]},
+		})
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -0,0 +1,78 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetUser
+func GetUser(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getUser"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		blocked, err := blockedBetween(r.Context(), mongoDb, userId, id)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if blocked {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var u models.User
+		if err := mongoDb.Collection("users").FindOne(r.Context(), bson.M{"_id": id}).Decode(&u); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		follows, err := followStates(r.Context(), mongoDb, userId, []int{id})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		applyFollowState(&u, follows)
+
+		// the counts are public, contact details are not
+		u.Password = ""
+		if id != userId {
+			u.Email = ""
+			u.Phone = ""
+		}
+
+		JSON(&models.UserResponse{
+			Code:   200,
+			Result: &u,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -0,0 +1,57 @@
+package operations
+
+import (
+	"net/http"
+	"strconv"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// GetUserFeed
+func GetUserFeed(mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "getUserFeed"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r)
+
+		id := v.Path("id").Int()
+
+		format := v.Path("format").String()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("id", id), zap.Any("format", format))
+
+		var user models.User
+		if err := mongoDb.Collection("users").FindOne(r.Context(), bson.M{"_id": id}).Decode(&user); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		writeArticleFeed(w, r, mongoDb, log, &articleFeed{
+			format: format,
+			title:  "frBook articles by " + user.Name,
+			path:   "/users/" + strconv.Itoa(id) + "/feed",
+			filter: bson.M{"user_id": id},
+		})
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
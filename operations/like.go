@@ -0,0 +1,97 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+
+	"fr_book_api/models"
+
+	"github.com/thoas/go-funk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// Like
+func Like(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "like"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		target := v.Path("target").String()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("target", target), zap.Any("id", id))
+
+		targetType, ok := targetFromPath(target)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		ownerId, ok, err := targetOwner(r.Context(), mongoDb, targetType, id, userId)
+		if err != nil {
+			log.Error("Unable to get target", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res, err := mongoDb.Collection(targetCollection(targetType)).UpdateOne(r.Context(), bson.M{"_id": id}, bson.M{"$addToSet": bson.M{"likes": userId}})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if res.ModifiedCount > 0 {
+			if targetType == models.TargetTypePost {
+				refreshRank(r.Context(), mongoDb, id)
+			}
+			bumpAffinity(r.Context(), mongoDb, userId, ownerId, "interactions")
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// articleReactions replaces the users who liked an article by their count
+// and whether the user is one of them.
+func articleReactions(a *models.Article, userId int) {
+	a.LikesCount = len(a.Likes)
+	a.Liked = funk.Contains(a.Likes, userId)
+	a.Likes = nil
+}
+
+// refreshRank recomputes the feed rank of a post after its engagement
+// changed and returns the post.
+func refreshRank(ctx context.Context, mongoDb *mongo.Database, id int) (*models.Post, error) {
+	var p models.Post
+	if err := mongoDb.Collection("posts").FindOne(ctx, bson.M{"_id": id}).Decode(&p); err != nil {
+		return nil, err
+	}
+	_, err := mongoDb.Collection("posts").UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"rank": p.FeedRank()}})
+	return &p, err
+}
+
+// -- end --
@@ -0,0 +1,55 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// ReactToChat
+func ReactToChat(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "reactToChat"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		emoji := v.Form("emoji").Optional().String()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("emoji", emoji))
+
+		// no emoji takes the reaction back
+		ev, ok := toChatHub(w, log, userId, &models.ChatEvent{
+			Kind:   models.ChatEventTypeReact,
+			ChatId: id,
+			Emoji:  emoji,
+		})
+		if !ok {
+			return
+		}
+
+		JSON(&models.ChatResponse{
+			Code:   200,
+			Result: ev.Chat,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -0,0 +1,71 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// ReadAllConversations
+func ReadAllConversations(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "readAllConversations"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId))
+
+		c, err := mongoDb.Collection("conversations").Find(r.Context(), bson.M{
+			"participant_ids": userId,
+			"members":         bson.M{"$elemMatch": bson.M{"user_id": userId, "unread_count": bson.M{"$gt": 0}}},
+		}, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			log.Error("Unable to find unread conversations", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer c.Close(r.Context())
+
+		var ids []int
+		for c.Next(r.Context()) {
+			var conv models.Conversation
+			if err := c.Decode(&conv); err != nil {
+				continue
+			}
+			ids = append(ids, conv.Id)
+		}
+
+		for _, id := range ids {
+			if _, err := markConversationRead(r.Context(), mongoDb, id, userId); err != nil {
+				log.Error("Unable to mark conversation read", zap.Int("id", id), zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
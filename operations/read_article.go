@@ -0,0 +1,125 @@
+package operations
+
+import (
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// ReadArticle
+func ReadArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "readArticle"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		progress := v.Form("progress").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("progress", progress))
+
+		if progress < 0 || progress > 100 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var article models.Article
+		if err := mongoDb.Collection("articles").FindOne(r.Context(), bson.M{"_id": id}).Decode(&article); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if isUnpublished(article.Status) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// authors reading their own articles are not counted
+		if article.UserId == userId {
+			JSON(&models.StatusResponse{
+				Code: 200,
+			}, w)
+			return
+		}
+
+		ct := time.Now()
+		readers := mongoDb.Collection("article_readers")
+		res, err := readers.UpdateOne(r.Context(), bson.M{
+			"article_id": id,
+			"user_id":    userId,
+		}, bson.M{
+			"$max": bson.M{"progress": progress},
+			"$set": bson.M{"updated_at": ct},
+			"$setOnInsert": &models.ArticleReader{
+				Id:        int(articleViewId.Generate().Int64()),
+				ArticleId: id,
+				UserId:    userId,
+				CreatedAt: ct,
+			},
+		}, options.Update().SetUpsert(true))
+		if err != nil && !models.IsDuplicateKeyError(err) {
+			log.Error("Unable to record progress", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if err == nil && res.UpsertedCount > 0 {
+			if err := countArticleStat(r.Context(), mongoDb, id, "readers_count", "readers", ct); err != nil {
+				log.Error("Unable to count reader", zap.Error(err))
+			}
+		}
+
+		if progress >= completedProgress {
+			// only the first time a reader gets to the end counts
+			res, err := readers.UpdateOne(r.Context(), bson.M{
+				"article_id": id,
+				"user_id":    userId,
+				"completed":  bson.M{"$ne": true},
+			}, bson.M{"$set": bson.M{"completed": true}})
+			if err != nil {
+				log.Error("Unable to record completion", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if res.ModifiedCount > 0 {
+				if err := countArticleStat(r.Context(), mongoDb, id, "completions_count", "completions", ct); err != nil {
+					log.Error("Unable to count completion", zap.Error(err))
+				}
+			}
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// completedProgress is how far, in percent, a reader has to scroll for the
+// article to count as read.
+const completedProgress = 90
+
+// -- end --
@@ -0,0 +1,56 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// ReadConversation
+func ReadConversation(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "readConversation"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		conv, err := markConversationRead(r.Context(), mongoDb, id, userId)
+		if err != nil {
+			log.Error("Unable to mark conversation read", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if conv == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		viewConversation(r.Context(), mongoDb, make(map[int]models.User), conv, userId)
+
+		JSON(&models.ConversationResponse{
+			Code:   200,
+			Result: conv,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -32,12 +32,20 @@ func RejectFriendRequest(sugar string, mongoDb *mongo.Database, logger *zap.Logg
 		}
 		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
 
-		_, err := mongoDb.Collection("friend_requests").DeleteOne(r.Context(), bson.M{"_id": id})
+		fr, ok := findFriendRequest(w, r, mongoDb, id, userId, true)
+		if !ok {
+			return
+		}
+
+		_, err := mongoDb.Collection("friend_requests").DeleteOne(r.Context(), bson.M{"_id": id, "to_id": userId})
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
+		notifyFriends(r.Context(), mongoDb, models.FriendEventTypeRejected, fr.FromId, userId, fr.Id)
+		notifyFriends(r.Context(), mongoDb, models.FriendEventTypePending, userId, 0, 0)
+
 		JSON(&models.StatusResponse{
 			Code: 200,
 		}, w)
@@ -0,0 +1,83 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"github.com/thoas/go-funk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// RemoveConversationMember
+func RemoveConversationMember(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "removeConversationMember"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		memberId := v.Path("member_id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("member_id", memberId))
+
+		// anyone may leave, only admins remove others
+		conv, ok := findGroup(w, r, mongoDb, id, userId, memberId != userId)
+		if !ok {
+			return
+		}
+		if !funk.Contains(conv.ParticipantIds, memberId) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res, err := mongoDb.Collection("conversations").UpdateOne(r.Context(), bson.M{"_id": id, "participant_ids": memberId}, bson.M{"$pull": bson.M{
+			"participant_ids": memberId,
+			"members":         bson.M{"user_id": memberId},
+		}})
+		if err != nil {
+			log.Error("Unable to remove group member", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if res.ModifiedCount == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// a group left without admins hands the role to its longest
+		// standing member
+		_, err = mongoDb.Collection("conversations").UpdateOne(r.Context(), bson.M{
+			"_id":          id,
+			"members.0":    bson.M{"$exists": true},
+			"members.role": bson.M{"$ne": models.ConversationRoleAdmin},
+		}, bson.M{"$set": bson.M{"members.0.role": models.ConversationRoleAdmin}})
+		if err != nil {
+			log.Error("Unable to hand over admin role", zap.Error(err))
+		}
+
+		announceGroup(id, userId, models.ChatKindLeft, memberId, "")
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -0,0 +1,66 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// RemoveFriend
+func RemoveFriend(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "removeFriend"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		friendId := v.Path("friend_id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("friend_id", friendId))
+
+		// older friendships may have been stored twice, one per direction
+		res, err := mongoDb.Collection("friends").DeleteMany(r.Context(), bson.M{"$or": []bson.M{
+			{"from_id": userId, "to_id": friendId},
+			{"from_id": friendId, "to_id": userId},
+		}})
+		if err != nil {
+			log.Error("Unable to remove friend", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if res.DeletedCount == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := leaveFriendLists(r.Context(), mongoDb, userId, friendId); err != nil {
+			log.Error("Unable to update friend lists", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		notifyFriends(r.Context(), mongoDb, models.FriendEventTypeRemoved, friendId, userId, 0)
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
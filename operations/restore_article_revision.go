@@ -0,0 +1,107 @@
+package operations
+
+import (
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// RestoreArticleRevision
+func RestoreArticleRevision(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "restoreArticleRevision"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		userType := v.Token("user_type").Optional().UserType()
+
+		id := v.Path("id").Int()
+
+		revision := v.Path("revision").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("revision", revision))
+
+		var article models.Article
+		if err := mongoDb.Collection("articles").FindOne(r.Context(), bson.M{"_id": id}).Decode(&article); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		isAdmin := userType == models.UserTypeAdmin
+		if !canSeeArticle(&article, userId, isAdmin) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if !canEditArticle(&article, userId, isAdmin) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		var old models.ArticleRevision
+		if err := mongoDb.Collection("article_revisions").FindOne(r.Context(), bson.M{"article_id": id, "revision": revision}).Decode(&old); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		set := bson.M{}
+		unset := bson.M{}
+		if !revisionChanges(articleRevision(&article), &old, set, unset) {
+			JSON(&models.StatusResponse{
+				Code: 200,
+			}, w)
+			return
+		}
+
+		// restoring is recorded as a new revision, so it can be undone too
+		rev := &old
+		rev.UserId = userId
+		rev.CreatedAt = time.Now()
+		rev.RestoredFrom = revision
+
+		ok, err := reviseArticle(r.Context(), mongoDb, &article, rev, set, unset)
+		if err != nil {
+			log.Error("Unable to restore revision", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			JSON(&models.StatusResponse{
+				Code:  409,
+				Error: "Article changed meanwhile",
+			}, w)
+			return
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -0,0 +1,134 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// SaveBookmark
+func SaveBookmark(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "saveBookmark"))
+	// -- init --
+	bookmarkId, _ := models.NewIDNode(12)
+	if mongoDb != nil {
+		mongoDb.Collection("bookmarks").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		targetType := v.Form("target_type").TargetType()
+
+		targetId := v.Form("target_id").Int()
+
+		collection := v.Form("collection").Optional().String()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("target_type", targetType), zap.Any("target_id", targetId), zap.Any("collection", collection))
+
+		// only what the user may see can be saved
+		visible, err := targetVisible(r.Context(), mongoDb, userId, targetType, targetId)
+		if err != nil {
+			log.Error("Unable to find bookmarked item", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !visible {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// saving an already saved item moves it to the given collection
+		_, err = mongoDb.Collection("bookmarks").UpdateOne(r.Context(), bson.M{
+			"user_id":     userId,
+			"target_type": targetType,
+			"target_id":   targetId,
+		}, bson.M{
+			"$set": bson.M{"collection": strings.TrimSpace(collection)},
+			"$setOnInsert": bson.M{
+				"_id":        int(bookmarkId.Generate().Int64()),
+				"created_at": time.Now(),
+			},
+		}, options.Update().SetUpsert(true))
+		if err != nil {
+			log.Error("Unable to save bookmark", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// targetCollection returns the mongo collection holding items of the given
+// target type.
+func targetCollection(t models.TargetType) string {
+	switch t {
+	case models.TargetTypeArticle:
+		return "articles"
+	default:
+		return "posts"
+	}
+}
+
+// targetVisible reports whether the item exists and the user may see it.
+func targetVisible(ctx context.Context, mongoDb *mongo.Database, userId int, targetType models.TargetType, targetId int) (bool, error) {
+	var authorId int
+	switch targetType {
+	case models.TargetTypeArticle:
+		var a models.Article
+		if err := mongoDb.Collection("articles").FindOne(ctx, bson.M{"_id": targetId}).Decode(&a); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return false, nil
+			}
+			return false, err
+		}
+		if !canSeeArticle(&a, userId, false) {
+			return false, nil
+		}
+		authorId = a.UserId
+	default:
+		var p models.Post
+		if err := mongoDb.Collection("posts").FindOne(ctx, bson.M{"_id": targetId}).Decode(&p); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return false, nil
+			}
+			return false, err
+		}
+		if ok, err := canSeePost(ctx, mongoDb, &p, userId); err != nil || !ok {
+			return false, err
+		}
+		authorId = p.UserId
+	}
+
+	blocked, err := blockedBetween(ctx, mongoDb, userId, authorId)
+	return !blocked, err
+}
+
+// -- end --
@@ -0,0 +1,270 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// Search
+func Search(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "search"))
+	// -- init --
+	if mongoDb != nil {
+		for _, s := range searchSources {
+			index := mongo.IndexModel{
+				Keys:    s.keys,
+				Options: options.Index().SetName(searchIndex).SetWeights(s.weights),
+			}
+			indexes := mongoDb.Collection(s.collection).Indexes()
+			_, err := indexes.CreateOne(context.Background(), index)
+			// a collection has a single text index, which is replaced when
+			// the searched fields change
+			if isIndexConflictError(err) {
+				if _, err = indexes.DropOne(context.Background(), searchIndex); err == nil {
+					_, err = indexes.CreateOne(context.Background(), index)
+				}
+			}
+			if err != nil {
+				oLog.Error("Unable to create search index", zap.String("collection", s.collection), zap.Error(err))
+			}
+		}
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		q := v.Query("q").String()
+
+		types := v.Query("type").Optional().SearchTypeArray()
+
+		start := v.Query("start").Optional().Int()
+
+		limit := v.Query("limit").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("q", q), zap.Any("type", types))
+
+		q = strings.TrimSpace(q)
+		if q == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		start, limit = pageBounds(start, limit)
+
+		wanted := make(map[models.SearchType]bool)
+		for _, t := range types {
+			wanted[t] = true
+		}
+
+		blocked, err := blockedIds(r.Context(), mongoDb, userId)
+		if err != nil {
+			log.Error("Unable to get blocks", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		// posts show up to whoever may see them, as in the feed
+		audience, err := visiblePosts(r.Context(), mongoDb, userId, false)
+		if err != nil {
+			log.Error("Unable to get visible posts", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		saved, err := savedTargets(r.Context(), mongoDb, userId, models.TargetTypeArticle)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var results []*models.SearchResult
+		total := 0
+		users := make(map[int]models.User)
+		var posts []*models.Post
+
+		for _, s := range searchSources {
+			if len(wanted) > 0 && !wanted[s.kind] {
+				continue
+			}
+
+			filter := bson.M{"$text": bson.M{"$search": q}}
+			for key, val := range s.visible {
+				filter[key] = val
+			}
+			if s.kind == models.SearchTypePost {
+				for key, val := range audience {
+					filter[key] = val
+				}
+			}
+			// nothing of users blocked either way shows up
+			if len(blocked) > 0 {
+				filter[s.owner] = bson.M{"$nin": blocked}
+			}
+
+			count, err := mongoDb.Collection(s.collection).CountDocuments(r.Context(), filter)
+			if err != nil {
+				log.Error("Unable to count matches", zap.String("collection", s.collection), zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			total += int(count)
+
+			// every source has to deliver enough hits to fill the requested
+			// page on its own, as they are merged by score afterwards
+			score := bson.M{"score": bson.M{"$meta": "textScore"}}
+			c, err := mongoDb.Collection(s.collection).Find(r.Context(), filter, options.Find().SetProjection(score).SetSort(score).SetLimit(int64(start+limit)))
+			if err != nil {
+				log.Error("Unable to search", zap.String("collection", s.collection), zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			for c.Next(r.Context()) {
+				var hit struct {
+					Score float64 `bson:"score"`
+				}
+				if err := c.Decode(&hit); err != nil {
+					continue
+				}
+
+				res := &models.SearchResult{
+					Type:  s.kind,
+					Score: hit.Score,
+				}
+
+				switch s.kind {
+				case models.SearchTypeUser:
+					var u models.User
+					if err := c.Decode(&u); err != nil {
+						continue
+					}
+					u.Password = ""
+					u.Email = ""
+					u.Phone = ""
+					res.User = &u
+				case models.SearchTypePost:
+					var p models.Post
+					if err := c.Decode(&p); err != nil {
+						continue
+					}
+					author, ok := lookupUser(r.Context(), mongoDb, users, p.UserId)
+					if !ok {
+						continue
+					}
+					p.Name = author.Name
+					p.ProfilePic = author.ProfilePic
+					p.Score = 0
+					res.Post = &p
+					posts = append(posts, &p)
+				case models.SearchTypeArticle:
+					var a models.Article
+					if err := c.Decode(&a); err != nil {
+						continue
+					}
+					author, ok := lookupUser(r.Context(), mongoDb, users, a.UserId)
+					if !ok {
+						continue
+					}
+					a.ProfilePic = author.ProfilePic
+					articleReactions(&a, userId)
+					a.Saved = saved[a.Id]
+					res.Article = &a
+				}
+
+				results = append(results, res)
+			}
+			c.Close(r.Context())
+		}
+
+		if err := preparePosts(r.Context(), mongoDb, userId, posts); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Score > results[j].Score
+		})
+
+		if start >= len(results) {
+			results = nil
+		} else if start+limit < len(results) {
+			results = results[start : start+limit]
+		} else {
+			results = results[start:]
+		}
+
+		JSON(&models.SearchResultListResponse{
+			Code:   200,
+			Result: results,
+			Start:  start,
+			Total:  total,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// searchIndex names the text index of every searchable collection, so that
+// it can be found and replaced when its fields change.
+const searchIndex = "search"
+
+// searchSource describes how one kind of item is searched.
+type searchSource struct {
+	kind       models.SearchType
+	collection string
+	keys       bson.D
+	weights    bson.M
+	// owner is the field holding the user an item belongs to.
+	owner string
+	// visible restricts matches to published items. Posts are further
+	// limited to the ones the user may see.
+	visible bson.M
+}
+
+var searchSources = []searchSource{
+	{
+		kind:       models.SearchTypeUser,
+		collection: "users",
+		keys:       bson.D{{Key: "name", Value: "text"}},
+		weights:    bson.M{"name": 10},
+		owner:      "_id",
+	},
+	{
+		kind:       models.SearchTypePost,
+		collection: "posts",
+		keys:       bson.D{{Key: "title", Value: "text"}, {Key: "content", Value: "text"}},
+		weights:    bson.M{"title": 5, "content": 1},
+		owner:      "user_id",
+		visible:    bson.M{"status": bson.M{"$nin": unpublished}},
+	},
+	{
+		kind:       models.SearchTypeArticle,
+		collection: "articles",
+		keys:       bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}, {Key: "tags", Value: "text"}, {Key: "content", Value: "text"}, {Key: "pdf_text", Value: "text"}},
+		weights:    bson.M{"title": 10, "tags": 5, "description": 3, "content": 1, "pdf_text": 1},
+		owner:      "user_id",
+		visible:    bson.M{"status": bson.M{"$nin": unpublished}},
+	},
+}
+
+// -- end --
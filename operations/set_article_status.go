@@ -0,0 +1,202 @@
+package operations
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// SetArticleStatus
+func SetArticleStatus(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "setArticleStatus"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		userType := v.Token("user_type").Optional().UserType()
+
+		id := v.Path("id").Int()
+
+		status := v.Form("status").String()
+
+		publishAt := v.Form("publish_at").Optional().DateTime()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("status", status))
+
+		var article models.Article
+		if err := mongoDb.Collection("articles").FindOne(r.Context(), bson.M{"_id": id}).Decode(&article); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		isAdmin := userType == models.UserTypeAdmin
+		if !canSeeArticle(&article, userId, isAdmin) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		ct := time.Now()
+		to, at, ok := articleTarget(status, publishAt, ct)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		set := bson.M{}
+		unset := bson.M{}
+		if !articleStatusChange(&article, to, at, article.UserId == userId, isAdmin, set, unset, ct) {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Status change not allowed",
+			}, w)
+			return
+		}
+
+		update := bson.M{}
+		if len(set) > 0 {
+			update["$set"] = set
+		}
+		if len(unset) > 0 {
+			update["$unset"] = unset
+		}
+
+		// matching on the current status makes concurrent changes fail
+		// instead of skipping a validation
+		res, err := mongoDb.Collection("articles").UpdateOne(r.Context(), bson.M{"_id": id, "status": statusFilter(article.Status)}, update)
+		if err != nil {
+			log.Error("Unable to update article status", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if res.MatchedCount == 0 {
+			JSON(&models.StatusResponse{
+				Code:  409,
+				Error: "Article changed meanwhile",
+			}, w)
+			return
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+type transitionRole int
+
+const (
+	byAuthor transitionRole = 1 << iota
+	byAdmin
+)
+
+// articleTransitions lists who may move an article from one status to
+// another. Authors may publish directly or ask an admin to review first.
+var articleTransitions = map[[2]models.PublishStatus]transitionRole{
+	{models.PublishStatusDraft, models.PublishStatusReview}:        byAuthor,
+	{models.PublishStatusDraft, models.PublishStatusPublished}:     byAuthor,
+	{models.PublishStatusDraft, models.PublishStatusScheduled}:     byAuthor,
+	{models.PublishStatusScheduled, models.PublishStatusDraft}:     byAuthor,
+	{models.PublishStatusScheduled, models.PublishStatusScheduled}: byAuthor,
+	{models.PublishStatusScheduled, models.PublishStatusPublished}: byAuthor,
+	{models.PublishStatusReview, models.PublishStatusDraft}:        byAuthor | byAdmin,
+	{models.PublishStatusReview, models.PublishStatusPublished}:    byAdmin,
+	{models.PublishStatusReview, models.PublishStatusScheduled}:    byAdmin,
+	{models.PublishStatusPublished, models.PublishStatusArchived}:  byAuthor | byAdmin,
+	{models.PublishStatusArchived, models.PublishStatusPublished}:  byAuthor | byAdmin,
+}
+
+// articleTarget parses the status an article should move to. Publishing
+// and scheduling follow the same rules as for new articles.
+func articleTarget(status string, publishAt time.Time, ct time.Time) (models.PublishStatus, *time.Time, bool) {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "review":
+		return models.PublishStatusReview, nil, true
+	case "archived":
+		return models.PublishStatusArchived, nil, true
+	}
+	return publishState(status, publishAt, ct)
+}
+
+// articleStatusChange checks that the user may move the article to the
+// given status and adds the resulting changes to set and unset.
+func articleStatusChange(article *models.Article, to models.PublishStatus, publishAt *time.Time, isAuthor, isAdmin bool, set, unset bson.M, ct time.Time) bool {
+	role := articleTransitions[[2]models.PublishStatus{article.Status, to}]
+	if !(isAuthor && role&byAuthor != 0) && !(isAdmin && role&byAdmin != 0) {
+		return false
+	}
+
+	if to == models.PublishStatusPublished {
+		unset["status"] = ""
+		unset["publish_at"] = ""
+		// restoring an archived article keeps its original date
+		if article.Status != models.PublishStatusArchived {
+			set["published_at"] = ct
+		}
+		return true
+	}
+
+	set["status"] = to
+	if publishAt != nil {
+		set["publish_at"] = publishAt
+	} else {
+		unset["publish_at"] = ""
+	}
+	return true
+}
+
+// canSeeArticle reports whether the user may look at the article. Articles
+// that are not published are only visible to their author, and to admins
+// while they await review.
+func canSeeArticle(article *models.Article, userId int, isAdmin bool) bool {
+	if !isUnpublished(article.Status) || article.UserId == userId {
+		return true
+	}
+	return isAdmin && article.Status == models.PublishStatusReview
+}
+
+// statusFilter matches documents in the given status. Published documents
+// carry no status at all.
+func statusFilter(s models.PublishStatus) interface{} {
+	if s == models.PublishStatusPublished {
+		return bson.M{"$exists": false}
+	}
+	return s
+}
+
+// articleTime is when the article went public, or when it was written if
+// it predates publishing timestamps.
+func articleTime(a *models.Article) time.Time {
+	if a.PublishedAt != nil {
+		return *a.PublishedAt
+	}
+	if a.CreatedAt != nil {
+		return *a.CreatedAt
+	}
+	return time.Time{}
+}
+
+// -- end --
@@ -0,0 +1,88 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"github.com/thoas/go-funk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// SetConversationRole
+func SetConversationRole(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "setConversationRole"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		memberId := v.Path("member_id").Int()
+
+		role := v.Form("role").ConversationRole()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("member_id", memberId), zap.Any("role", role))
+
+		conv, ok := findGroup(w, r, mongoDb, id, userId, true)
+		if !ok {
+			return
+		}
+		if !funk.Contains(conv.ParticipantIds, memberId) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		filter := bson.M{"_id": id, "participant_ids": memberId}
+		if role != models.ConversationRoleAdmin {
+			// the last admin can't step down
+			filter["members"] = bson.M{"$elemMatch": bson.M{
+				"user_id": bson.M{"$ne": memberId},
+				"role":    models.ConversationRoleAdmin,
+			}}
+		}
+
+		err := mongoDb.Collection("conversations").FindOneAndUpdate(r.Context(), filter, bson.M{"$set": bson.M{
+			"members.$[m].role": role,
+		}}, options.FindOneAndUpdate().
+			SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"m.user_id": memberId}}}).
+			SetReturnDocument(options.After)).Decode(conv)
+		if err == mongo.ErrNoDocuments {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "A group needs an admin",
+			}, w)
+			return
+		}
+		if err != nil {
+			log.Error("Unable to set group role", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		viewConversation(r.Context(), mongoDb, make(map[int]models.User), conv, userId)
+
+		JSON(&models.ConversationResponse{
+			Code:   200,
+			Result: conv,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
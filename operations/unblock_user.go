@@ -12,9 +12,9 @@ import (
 	// -- end --
 )
 
-// LikePost
-func LikePost(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
-	oLog := logger.With(zap.String("op", "likePost"))
+// UnblockUser
+func UnblockUser(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "unblockUser"))
 	// -- init --
 	// -- end --
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -32,7 +32,11 @@ func LikePost(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Ha
 		}
 		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
 
-		mongoDb.Collection("posts").UpdateOne(r.Context(), bson.M{"_id": id}, bson.M{"$addToSet": bson.M{"likes": userId}})
+		if _, err := mongoDb.Collection("blocks").DeleteOne(r.Context(), bson.M{"from_id": userId, "to_id": id}); err != nil {
+			log.Error("Unable to unblock user", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
 
 		JSON(&models.StatusResponse{
 			Code: 200,
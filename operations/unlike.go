@@ -0,0 +1,61 @@
+package operations
+
+import (
+	"net/http"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// Unlike
+func Unlike(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "unlike"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		target := v.Path("target").String()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("target", target), zap.Any("id", id))
+
+		targetType, ok := targetFromPath(target)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res, err := mongoDb.Collection(targetCollection(targetType)).UpdateOne(r.Context(), bson.M{"_id": id}, bson.M{"$pull": bson.M{"likes": userId}})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if res.ModifiedCount > 0 && targetType == models.TargetTypePost {
+			refreshRank(r.Context(), mongoDb, id)
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -1,7 +1,9 @@
 package operations
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"fr_book_api/models"
 
@@ -22,6 +24,8 @@ func UpdateArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 
 		userId := v.Token("user_id").Int()
 
+		userType := v.Token("user_type").Optional().UserType()
+
 		id := v.Path("id").Int()
 
 		authorName := v.Form("author_name").Optional().String()
@@ -38,6 +42,10 @@ func UpdateArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 
 		pdf := v.Form("pdf").Optional().String()
 
+		status := v.Form("status").Optional().String()
+
+		publishAt := v.Form("publish_at").Optional().DateTime()
+
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
 		// -- code --
 		if !v.Valid() {
@@ -46,20 +54,117 @@ func UpdateArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 		}
 		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("title", title), zap.Any("tags", tags), zap.Any("content", content), zap.Any("photo", photo))
 
-		_, err := mongoDb.Collection("articles").UpdateOne(r.Context(), bson.M{"_id": id}, bson.M{"$set": bson.M{
-			"title":       title,
-			"tags":        tags,
-			"content":     content,
-			"photo":       photo,
-			"description": description,
-			"pdf":         pdf,
-			"author_name": authorName,
-		}})
+		var article models.Article
+		err := mongoDb.Collection("articles").FindOne(r.Context(), bson.M{"_id": id}).Decode(&article)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		isAdmin := userType == models.UserTypeAdmin
+		if !canSeeArticle(&article, userId, isAdmin) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		isAuthor := article.UserId == userId
+		if !canEditArticle(&article, userId, isAdmin) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if len(content) > models.MaxMarkdownLength {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Content too long",
+			}, w)
+			return
+		}
+
+		// only fields that were sent are changed
+		next := article
+		if v.HasForm("title") {
+			next.Title = title
+		}
+		if v.HasForm("author_name") {
+			next.AuthorName = authorName
+		}
+		if v.HasForm("description") {
+			next.Description = description
+		}
+		if v.HasForm("tags") {
+			next.Tags = models.NormalizeTags(tags)
+		}
+		if v.HasForm("content") {
+			next.Content = content
+		}
+		if v.HasForm("photo") {
+			next.Photo = photo
+		}
+		if v.HasForm("pdf") {
+			next.Pdf = pdf
+		}
+
+		if next.Pdf != article.Pdf && next.Pdf != "" && !isPdfAsset(next.Pdf) {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Invalid PDF",
+			}, w)
+			return
+		}
+
+		set := bson.M{}
+		unset := bson.M{}
+		changed := revisionChanges(articleRevision(&article), articleRevision(&next), set, unset)
+
+		if v.HasForm("status") || v.HasForm("publish_at") {
+			ct := time.Now()
+			to, at, ok := articleTarget(status, publishAt, ct)
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if !articleStatusChange(&article, to, at, isAuthor, isAdmin, set, unset, ct) {
+				JSON(&models.StatusResponse{
+					Code:  400,
+					Error: "Status change not allowed",
+				}, w)
+				return
+			}
+		}
+
+		if len(set) == 0 && len(unset) == 0 {
+			JSON(&models.StatusResponse{
+				Code: 200,
+			}, w)
+			return
+		}
+
+		var ok bool
+		if changed {
+			rev := articleRevision(&next)
+			rev.UserId = userId
+			rev.CreatedAt = time.Now()
+			ok, err = reviseArticle(r.Context(), mongoDb, &article, rev, set, unset)
+		} else {
+			ok, err = updateArticleStatus(r.Context(), mongoDb, &article, set, unset)
+		}
 		if err != nil {
 			log.Error("Unable to update article", zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		if !ok {
+			JSON(&models.StatusResponse{
+				Code:  409,
+				Error: "Article changed meanwhile",
+			}, w)
+			return
+		}
 
 		JSON(&models.StatusResponse{
 			Code: 200,
@@ -69,4 +174,58 @@ func UpdateArticle(sugar string, mongoDb *mongo.Database, logger *zap.Logger) ht
 }
 
 // -- extra --
+
+// canEditArticle reports whether the user may change the article's content.
+// Only authors edit their articles, except for admins reviewing them.
+func canEditArticle(article *models.Article, userId int, isAdmin bool) bool {
+	return article.UserId == userId || (isAdmin && article.Status == models.PublishStatusReview)
+}
+
+// revisionChanges adds the fields that differ between two revisions to set,
+// or to unset when they were cleared, and reports whether there were any.
+func revisionChanges(from, to *models.ArticleRevision, set, unset bson.M) bool {
+	changed := false
+	for _, f := range revisionFields(from, to) {
+		if f.old == f.new {
+			continue
+		}
+		changed = true
+		// a new PDF has to be processed again
+		if f.name == "pdf" {
+			unset["pdf_info"] = ""
+			unset["pdf_text"] = ""
+		}
+		if f.new == "" {
+			unset[f.name] = ""
+		} else if f.name == "tags" {
+			set[f.name] = to.Tags
+		} else {
+			set[f.name] = f.new
+		}
+	}
+	return changed
+}
+
+// updateArticleStatus applies a change that leaves the content alone, as
+// long as the article was not changed since it was read.
+func updateArticleStatus(ctx context.Context, db *mongo.Database, article *models.Article, set, unset bson.M) (bool, error) {
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	res, err := db.Collection("articles").UpdateOne(ctx, bson.M{
+		"_id":      article.Id,
+		"status":   statusFilter(article.Status),
+		"revision": revisionFilter(article.Revision),
+	}, update)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
 // -- end --
@@ -0,0 +1,84 @@
+package operations
+
+import (
+	"net/http"
+	"strings"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// UpdateConversation
+func UpdateConversation(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "updateConversation"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		title := v.Form("title").Optional().String()
+
+		avatar := v.Form("avatar").Optional().String()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		if _, ok := findGroup(w, r, mongoDb, id, userId, true); !ok {
+			return
+		}
+
+		set := bson.M{}
+		if v.HasForm("title") {
+			title = strings.TrimSpace(title)
+			if title == "" || len(title) > maxGroupTitle {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			set["title"] = title
+		}
+		if v.HasForm("avatar") {
+			set["avatar"] = avatar
+		}
+		if len(set) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var conv models.Conversation
+		err := mongoDb.Collection("conversations").FindOneAndUpdate(r.Context(), bson.M{"_id": id}, bson.M{"$set": set},
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&conv)
+		if err != nil {
+			log.Error("Unable to update group", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		announceGroup(id, userId, models.ChatKindUpdated, 0, conv.Title)
+
+		viewConversation(r.Context(), mongoDb, make(map[int]models.User), &conv, userId)
+
+		JSON(&models.ConversationResponse{
+			Code:   200,
+			Result: &conv,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -0,0 +1,98 @@
+package operations
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// UpdateFriendList
+func UpdateFriendList(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "updateFriendList"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		name := v.Form("name").Optional().String()
+
+		memberIds := v.Form("member_ids").Optional().IntArray()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		set := bson.M{"updated_at": time.Now()}
+
+		if v.HasForm("name") {
+			name = strings.TrimSpace(name)
+			if name == "" || len(name) > maxFriendListName {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			set["name"] = name
+		}
+
+		if v.HasForm("member_ids") {
+			members, ok, err := listMembers(r.Context(), mongoDb, userId, memberIds)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if !ok {
+				JSON(&models.StatusResponse{
+					Code:  400,
+					Error: "Lists can only hold friends",
+				}, w)
+				return
+			}
+			set["member_ids"] = members
+		}
+
+		var list models.FriendList
+		err := mongoDb.Collection("friend_lists").FindOneAndUpdate(r.Context(), bson.M{"_id": id, "user_id": userId}, bson.M{"$set": set},
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&list)
+		if err == mongo.ErrNoDocuments {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if models.IsDuplicateKeyError(err) {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "List already exists",
+			}, w)
+			return
+		}
+		if err != nil {
+			log.Error("Unable to update friend list", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		JSON(&models.FriendListResponse{
+			Code:   200,
+			Result: &list,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -1,6 +1,7 @@
 package operations
 
 import (
+	"context"
 	"net/http"
 
 	"fr_book_api/models"
@@ -24,6 +25,8 @@ func UpdateMe(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Ha
 
 		profilePic := v.Form("profile_pic").Optional().String()
 
+		private := v.Form("private").Optional().Bool()
+
 		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
 		// -- code --
 		if !v.Valid() {
@@ -32,9 +35,30 @@ func UpdateMe(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Ha
 		}
 		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("profile_pic", profilePic))
 
-		mongoDb.Collection("users").UpdateOne(r.Context(), bson.M{"_id": userId}, bson.M{"$set": bson.M{
-			"profile_pic": profilePic,
-		}})
+		set := bson.M{}
+		if v.HasForm("profile_pic") {
+			set["profile_pic"] = profilePic
+		}
+		if v.HasForm("private") {
+			set["private"] = private
+		}
+
+		if len(set) > 0 {
+			if _, err := mongoDb.Collection("users").UpdateOne(r.Context(), bson.M{"_id": userId}, bson.M{"$set": set}); err != nil {
+				log.Error("Unable to update user", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		// a public account has nobody left to approve
+		if v.HasForm("private") && !private {
+			if err := approveFollows(r.Context(), mongoDb, userId); err != nil {
+				log.Error("Unable to approve follows", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
 
 		JSON(&models.StatusResponse{
 			Code: 200,
@@ -44,4 +68,30 @@ func UpdateMe(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Ha
 }
 
 // -- extra --
+
+// approveFollows approves every follow of the user still waiting for it.
+func approveFollows(ctx context.Context, mongoDb *mongo.Database, userId int) error {
+	c, err := mongoDb.Collection("follows").Find(ctx, bson.M{"to_id": userId, "pending": true})
+	if err != nil {
+		return err
+	}
+
+	defer c.Close(ctx)
+
+	for c.Next(ctx) {
+		var f models.Follow
+		if err := c.Decode(&f); err != nil {
+			continue
+		}
+		res, err := mongoDb.Collection("follows").UpdateOne(ctx, bson.M{"_id": f.Id, "pending": true}, bson.M{"$unset": bson.M{"pending": ""}})
+		if err != nil {
+			return err
+		}
+		if res.ModifiedCount > 0 {
+			countFollow(ctx, mongoDb, f.FromId, f.ToId, 1)
+		}
+	}
+	return c.Err()
+}
+
 // -- end --
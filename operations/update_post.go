@@ -0,0 +1,169 @@
+package operations
+
+import (
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// UpdatePost
+func UpdatePost(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "updatePost"))
+	// -- init --
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		title := v.Form("title").Optional().String()
+
+		content := v.Form("content").Optional().String()
+
+		video := v.Form("video").Optional().String()
+
+		image := v.Form("image").Optional().String()
+
+		status := v.Form("status").Optional().String()
+
+		publishAt := v.Form("publish_at").Optional().DateTime()
+
+		visibility := v.Form("visibility").Optional().String()
+
+		listId := v.Form("list_id").Optional().Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("status", status))
+
+		var post models.Post
+		if err := mongoDb.Collection("posts").FindOne(r.Context(), bson.M{"_id": id, "user_id": userId}).Decode(&post); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		// who gets to see a post can be changed after it's published, but not
+		// what it says
+		published := !isUnpublished(post.Status)
+		edited := false
+		for _, f := range []string{"title", "content", "video", "image", "status", "publish_at"} {
+			edited = edited || v.HasForm(f)
+		}
+		if published && edited {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Post already published",
+			}, w)
+			return
+		}
+
+		set := bson.M{}
+		unset := bson.M{}
+
+		if v.HasForm("title") {
+			set["title"] = title
+		}
+		if v.HasForm("content") {
+			set["content"] = content
+		}
+		if v.HasForm("video") {
+			set["video"] = video
+		}
+		if v.HasForm("image") {
+			set["image"] = image
+		}
+
+		if v.HasForm("status") || v.HasForm("publish_at") {
+			ct := time.Now()
+			pStatus, pAt, ok := publishState(status, publishAt, ct)
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			applyPublishState(set, unset, pStatus, pAt, ct)
+		}
+
+		if v.HasForm("visibility") {
+			pVisibility, ok := parseVisibility(visibility)
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			switch pVisibility {
+			case models.VisibilityPublic:
+				unset["visibility"] = ""
+				unset["list_id"] = ""
+			case models.VisibilityList:
+				ok, err := ownsFriendList(r.Context(), mongoDb, userId, listId)
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				if !ok {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				set["visibility"] = pVisibility
+				set["list_id"] = listId
+			default:
+				set["visibility"] = pVisibility
+				unset["list_id"] = ""
+			}
+		}
+
+		update := bson.M{}
+		if len(set) > 0 {
+			update["$set"] = set
+		}
+		if len(unset) > 0 {
+			update["$unset"] = unset
+		}
+
+		if len(update) > 0 {
+			// the status check guards against the background hub publishing
+			// the post in the meantime
+			filter := bson.M{"_id": id, "status": post.Status}
+			if published {
+				filter = bson.M{"_id": id}
+			}
+			res, err := mongoDb.Collection("posts").UpdateOne(r.Context(), filter, update)
+			if err != nil {
+				log.Error("Unable to update post", zap.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if res.MatchedCount == 0 {
+				JSON(&models.StatusResponse{
+					Code:  400,
+					Error: "Post already published",
+				}, w)
+				return
+			}
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -12,6 +12,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"fr_book_api/models"
+
 	"github.com/disintegration/imaging"
 	"go.uber.org/zap"
 )
@@ -26,6 +28,10 @@ func Upload(bucketName string, logger *zap.Logger) (http.Handler, error) {
 		}
 
 		url, err := UploadIO(r.Context(), h.Filename, f)
+		if err == models.ErrInvalidPdf {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
 		if err != nil {
 			logger.Error("Unable to upload", zap.String("file", h.Filename), zap.Error(err))
 			w.WriteHeader(http.StatusInternalServerError)
@@ -47,6 +53,9 @@ func UploadIO(ctxt context.Context, name string, f io.Reader) (string, error) {
 	if _, err := buffer.ReadFrom(f); err != nil {
 		return "", err
 	}
+	if ext == "pdf" && !models.IsPdf(buffer.Bytes()) {
+		return "", models.ErrInvalidPdf
+	}
 	hash := md5.Sum(buffer.Bytes())
 	filename := hex.EncodeToString(hash[:]) + "." + ext
 	os.WriteFile(filepath.Join("assets", filename), buffer.Bytes(), os.ModePerm)
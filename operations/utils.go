@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	// -- import --
+	"go.mongodb.org/mongo-driver/mongo"
 	// -- end --
 )
 
@@ -19,4 +20,31 @@ func JSON(o interface{}, w http.ResponseWriter) {
 }
 
 // -- code --
+
+// isIndexConflictError reports whether an index could not be created
+// because one with the same name but other keys or options exists.
+func isIndexConflictError(err error) bool {
+	e, ok := err.(mongo.CommandError)
+	return ok && (e.Code == 85 || e.Code == 86)
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageBounds clamps the start and limit of a paginated request.
+func pageBounds(start, limit int) (int, int) {
+	if start < 0 {
+		start = 0
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return start, limit
+}
+
 // -- end --
@@ -0,0 +1,106 @@
+package operations
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// ViewStory
+func ViewStory(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "viewStory"))
+	// -- init --
+	viewId, _ := models.NewIDNode(14)
+	if mongoDb != nil {
+		mongoDb.Collection("story_views").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "story_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id))
+
+		ct := time.Now()
+
+		var story models.Story
+		if err := mongoDb.Collection("stories").FindOne(r.Context(), bson.M{"_id": id, "expires_at": bson.M{"$gt": ct}}).Decode(&story); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		// authors looking at their own story are not counted
+		if story.UserId == userId {
+			JSON(&models.StatusResponse{
+				Code: 200,
+			}, w)
+			return
+		}
+
+		audience := models.VisibilityFriends
+		if story.ListId != 0 {
+			audience = models.VisibilityList
+		}
+		ok, err := inAudience(r.Context(), mongoDb, story.UserId, userId, audience, story.ListId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res, err := mongoDb.Collection("story_views").UpdateOne(r.Context(), bson.M{"story_id": id, "user_id": userId}, bson.M{
+			"$setOnInsert": &models.StoryView{
+				Id:        int(viewId.Generate().Int64()),
+				StoryId:   id,
+				UserId:    userId,
+				CreatedAt: ct,
+				ExpiresAt: story.ExpiresAt,
+			},
+		}, options.Update().SetUpsert(true))
+		if err != nil && !models.IsDuplicateKeyError(err) {
+			log.Error("Unable to record view", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if err == nil && res.UpsertedCount > 0 {
+			mongoDb.Collection("stories").UpdateOne(r.Context(), bson.M{"_id": id}, bson.M{"$inc": bson.M{"views_count": 1}})
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+// -- end --
@@ -0,0 +1,167 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fr_book_api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	// -- imports --
+	// -- end --
+)
+
+// VotePoll
+func VotePoll(sugar string, mongoDb *mongo.Database, logger *zap.Logger) http.Handler {
+	oLog := logger.With(zap.String("op", "votePoll"))
+	// -- init --
+	voteId, _ := models.NewIDNode(11)
+	if mongoDb != nil {
+		// one vote per user per poll
+		mongoDb.Collection("poll_votes").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "post_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+	}
+	// -- end --
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := models.NewValidator(r).Secret(sugar)
+
+		userId := v.Token("user_id").Int()
+
+		id := v.Path("id").Int()
+
+		optionIds := v.Form("option_ids").IntArray()
+
+		log := oLog.With(zap.String("ip", r.Header.Get("X-Real-IP")))
+		// -- code --
+		if !v.Valid() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		log.Debug("Start Operation", zap.Any("user_id", userId), zap.Any("id", id), zap.Any("option_ids", optionIds))
+
+		var post models.Post
+		if err := mongoDb.Collection("posts").FindOne(r.Context(), bson.M{"_id": id}).Decode(&post); err != nil {
+			if err == mongo.ErrNoDocuments {
+				w.WriteHeader(http.StatusNotFound)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if post.Poll == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		ct := time.Now()
+		if post.Poll.Closed || (post.Poll.ClosesAt != nil && !post.Poll.ClosesAt.After(ct)) {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Poll is closed",
+			}, w)
+			return
+		}
+
+		// dedupe and check the options against the poll
+		var choices []int
+		seen := make(map[int]bool)
+		for _, o := range optionIds {
+			if o < 0 || o >= len(post.Poll.Options) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if !seen[o] {
+				seen[o] = true
+				choices = append(choices, o)
+			}
+		}
+
+		if len(choices) == 0 || (len(choices) > 1 && !post.Poll.MultiChoice) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		inc := bson.M{"poll.total_votes": 1}
+		for _, o := range choices {
+			inc[fmt.Sprintf("poll.options.%d.votes", o)] = 1
+		}
+
+		// the vote and the counts on the post are written together, so they
+		// can't drift apart
+		sess, err := mongoDb.Client().StartSession()
+		if err != nil {
+			log.Error("Unable to start session", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		defer sess.EndSession(r.Context())
+
+		_, err = sess.WithTransaction(r.Context(), func(sc mongo.SessionContext) (interface{}, error) {
+			_, err := mongoDb.Collection("poll_votes").InsertOne(sc, &models.PollVote{
+				Id:        int(voteId.Generate().Int64()),
+				PostId:    id,
+				UserId:    userId,
+				OptionIds: choices,
+				CreatedAt: ct,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			res, err := mongoDb.Collection("posts").UpdateOne(sc, bson.M{"_id": id, "poll.closed": bson.M{"$ne": true}}, bson.M{"$inc": inc})
+			if err != nil {
+				return nil, err
+			}
+			if res.MatchedCount == 0 {
+				return nil, errPollClosed
+			}
+			return nil, nil
+		})
+		if models.IsDuplicateKeyError(err) {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Already voted",
+			}, w)
+			return
+		}
+		if err == errPollClosed {
+			JSON(&models.StatusResponse{
+				Code:  400,
+				Error: "Poll is closed",
+			}, w)
+			return
+		}
+		if isNoTransactionsError(err) {
+			log.Error("Voting in polls needs MongoDB to run as a replica set or sharded cluster", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if err != nil {
+			log.Error("Unable to record vote", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		JSON(&models.StatusResponse{
+			Code: 200,
+		}, w)
+		// -- end --
+	})
+}
+
+// -- extra --
+
+// errPollClosed is returned when a poll was closed meanwhile.
+var errPollClosed = errors.New("poll closed")
+
+// -- end --